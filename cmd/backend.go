@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// newBackendCmd returns the subcommand generating code with the backend b
+func newBackendCmd(b generator.Backend) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   b.Name + " [flags] <struct> <directory/files...>",
+		Short: b.Short,
+		Long:  ``,
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.StructName = args[0]
+
+			err := generateFile(b, opts, load, cmd.Flags(), funcs, args[1:], output)
+			if err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", fmt.Sprintf("output file name; default srcdir/<struct>_%s.crud.go", b.Name))
+	if b.Flags != nil {
+		b.Flags(cmd.Flags())
+	}
+
+	return cmd
+}
+
+// generateFile generates the functions fns for the struct o.StructName in the
+// sources src (directory/files), loaded with l, with the backend b, configured
+// by o and the backend flags in fs. The code is written to output, which defaults to
+// srcdir/<struct>_<backend>.crud.go. If output is in another directory, the code
+// is generated into the package of that directory. The helper types used by the
+// generated code are written to generator.TypesFileName in the same directory
+func generateFile(b generator.Backend, o generator.Options, l loadOptions, fs *pflag.FlagSet, fns []string, src []string, output string) error {
+	pkg, t, dir, err := getPkgAndType(o.StructName, l, src...)
+	if err != nil {
+		return err
+	}
+	o.Pkg = pkg
+	o.Struct = t
+
+	if output == "" {
+		baseName := fmt.Sprintf("%s_%s.crud.go", o.StructName, b.Name)
+		output = filepath.Join(dir, strings.ToLower(baseName))
+	}
+	outputDir := filepath.Dir(output)
+	o.ExternalPkg, err = isOtherDir(dir, outputDir)
+	if err != nil {
+		return err
+	}
+	if o.ExternalPkg {
+		if pkg.Path() == commandLinePkgPath {
+			return fmt.Errorf("the import path of the package of %s is unknown, pass its directory instead of files to generate into another package", o.StructName)
+		}
+		if o.PkgName == "" {
+			o.PkgName = outputPkgName(outputDir)
+		}
+	}
+
+	gen, err := b.New(o, fs)
+	if err != nil {
+		return fmt.Errorf("could not initialize a new generator: %s", err)
+	}
+
+	functions := make([]generator.Function, len(fns))
+	for i, f := range fns {
+		functions[i] = generator.Function(f)
+	}
+
+	var out bytes.Buffer
+	err = gen.Generate(&out, functions...)
+	if err != nil {
+		return err
+	}
+
+	// Write to file.
+	err = os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return fmt.Errorf("creating output directory: %s", err)
+	}
+	err = ioutil.WriteFile(output, out.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("writing output: %s", err)
+	}
+
+	pkgName := o.PkgName
+	if pkgName == "" {
+		pkgName = pkg.Name()
+	}
+	typesFile := filepath.Join(outputDir, generator.TypesFileName)
+	err = ioutil.WriteFile(typesFile, generator.Types(pkgName), 0644)
+	if err != nil {
+		return fmt.Errorf("writing helper types: %s", err)
+	}
+
+	return nil
+}
+
+// isOtherDir reports whether the directory b is another directory than a
+func isOtherDir(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+
+	return absA != absB, nil
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const defaultConfigFile = "cruder.yaml"
+
+type (
+	// config is the content of a config file for the generate command. The
+	// settings at the top level are the defaults for all structs
+	config struct {
+		Backend    string         `yaml:"backend"`
+		Src        string         `yaml:"src"`
+		Pkg        string         `yaml:"pkg"`
+		Fn         []string       `yaml:"fn"`
+		SkipSuffix *bool          `yaml:"skipsuffix"`
+		NoContext  *bool          `yaml:"nocontext"`
+		Tags       []string       `yaml:"tags"`
+		Tests      *bool          `yaml:"tests"`
+		Structs    []structConfig `yaml:"structs"`
+	}
+
+	// structConfig contains the settings of a struct in a config file.
+	// Flags contains the flags of the backend, e.g. conflictfields for pg
+	structConfig struct {
+		Name            string                 `yaml:"name"`
+		Backend         string                 `yaml:"backend"`
+		Src             string                 `yaml:"src"`
+		Output          string                 `yaml:"output"`
+		Pkg             string                 `yaml:"pkg"`
+		Table           string                 `yaml:"table"`
+		Fn              []string               `yaml:"fn"`
+		SkipSuffix      *bool                  `yaml:"skipsuffix"`
+		NoContext       *bool                  `yaml:"nocontext"`
+		Tags            []string               `yaml:"tags"`
+		Tests           *bool                  `yaml:"tests"`
+		PrimaryFields   []string               `yaml:"primaryfield"`
+		SoftDeleteField string                 `yaml:"softdeletefield"`
+		ReadFields      []string               `yaml:"readfields"`
+		WriteFields     []string               `yaml:"writefields"`
+		Flags           map[string]interface{} `yaml:"flags"`
+	}
+)
+
+// newGenerateCmd returns the command generating code for the structs listed in
+// a config file
+func newGenerateCmd() *cobra.Command {
+	var configFile string
+
+	cmd := &cobra.Command{
+		Use:   "generate [flags]",
+		Short: "Generates CRUD methods for the structs listed in a config file",
+		Long: `Generates CRUD methods for the structs listed in a YAML or JSON config file,
+so that a single go:generate line regenerates the code for all of them. Relative
+paths in the config file are relative to the directory of the file. The other
+flags of cruder are not used, the settings are read from the config file only:
+
+backend: pg          # default backend for all structs
+src: ./models        # default directory of the structs, default to the directory of the file
+fn: [create, get, list, update, delete]
+nocontext: false
+skipsuffix: false
+tags: [integration]  # build tags used to load the structs
+tests: false         # include the test files when loading the structs
+structs:
+  - name: Foo
+    table: foos
+    output: ./models/foo_pg.crud.go
+    primaryfield: [ID]
+    softdeletefield: DeletedAt
+    readfields: [ID, Name]
+    writefields: [Name]
+    flags:           # flags of the backend
+      conflictfields: [Name]
+      copyin: true
+`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := generateFromConfig(configFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", defaultConfigFile, "config file listing the structs to generate code for")
+
+	return cmd
+}
+
+// generateFromConfig generates the code for every struct in the config file
+func generateFromConfig(configFile string) error {
+	b, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err)
+	}
+
+	var c config
+	err = yaml.Unmarshal(b, &c)
+	if err != nil {
+		return fmt.Errorf("parsing config %s: %s", configFile, err)
+	}
+
+	dir := filepath.Dir(configFile)
+	for _, s := range c.Structs {
+		err = generateStruct(c, s, dir)
+		if err != nil {
+			return fmt.Errorf("generating %s: %s", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// generateStruct generates the code for the struct s, using the settings of
+// c as defaults. Relative paths are resolved from dir
+func generateStruct(c config, s structConfig, dir string) error {
+	if s.Name == "" {
+		return fmt.Errorf("missing struct name")
+	}
+
+	backendName := firstNonEmpty(s.Backend, c.Backend)
+	if backendName == "" {
+		return fmt.Errorf("missing backend")
+	}
+	b, ok := generator.Lookup(backendName)
+	if !ok {
+		return fmt.Errorf("unknown backend %s", backendName)
+	}
+
+	fs := pflag.NewFlagSet(b.Name, pflag.ContinueOnError)
+	if b.Flags != nil {
+		b.Flags(fs)
+	}
+	if s.Table != "" {
+		if err := fs.Set("table", s.Table); err != nil {
+			return fmt.Errorf("setting table: %s", err)
+		}
+	}
+	for name, value := range s.Flags {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag %s for backend %s", name, b.Name)
+		}
+		if err := fs.Set(name, flagValue(value)); err != nil {
+			return fmt.Errorf("setting flag %s: %s", name, err)
+		}
+	}
+
+	o := generator.Options{
+		StructName:      s.Name,
+		CommandLine:     opts.CommandLine,
+		PkgName:         firstNonEmpty(s.Pkg, c.Pkg),
+		SkipSuffix:      firstBool(s.SkipSuffix, c.SkipSuffix),
+		NoContext:       firstBool(s.NoContext, c.NoContext),
+		PrimaryFields:   s.PrimaryFields,
+		SoftDeleteField: s.SoftDeleteField,
+		ReadFields:      s.ReadFields,
+		WriteFields:     s.WriteFields,
+	}
+
+	l := loadOptions{
+		Tags:  s.Tags,
+		Tests: firstBool(s.Tests, c.Tests),
+	}
+	if len(l.Tags) == 0 {
+		l.Tags = c.Tags
+	}
+
+	fns := s.Fn
+	if len(fns) == 0 {
+		fns = c.Fn
+	}
+	if len(fns) == 0 {
+		fns = []string{
+			string(generator.Create),
+			string(generator.Get),
+			string(generator.List),
+			string(generator.Update),
+			string(generator.Delete),
+		}
+	}
+
+	src := resolvePath(dir, firstNonEmpty(s.Src, c.Src, "."))
+	var output string
+	if s.Output != "" {
+		output = resolvePath(dir, s.Output)
+	}
+
+	return generateFile(b, o, l, fs, fns, []string{src}, output)
+}
+
+// flagValue returns the value of a flag in a config file as passed on the
+// command line, lists are joined with commas
+func flagValue(v interface{}) string {
+	if l, ok := v.([]interface{}); ok {
+		values := make([]string, len(l))
+		for i, x := range l {
+			values[i] = fmt.Sprint(x)
+		}
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprint(v)
+}
+
+// resolvePath returns p relative to dir, unless it is absolute
+func resolvePath(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(dir, p)
+}
+
+// firstNonEmpty returns the first string which is not empty
+func firstNonEmpty(s ...string) string {
+	for _, x := range s {
+		if x != "" {
+			return x
+		}
+	}
+
+	return ""
+}
+
+// firstBool returns the value of the first bool which is set
+func firstBool(b ...*bool) bool {
+	for _, x := range b {
+		if x != nil {
+			return *x
+		}
+	}
+
+	return false
+}
@@ -2,90 +2,136 @@ package cmd
 
 import (
 	"fmt"
-	"go/ast"
-	"go/build"
-	"go/importer"
-	"go/parser"
-	"go/token"
 	"go/types"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
-// getPkgAndType parses src (directories/files) and return the *types.Package,
+// commandLinePkgPath is the import path of packages made of files passed on
+// the command line
+const commandLinePkgPath = "command-line-arguments"
+
+// loadOptions contains the settings used to load the package of the struct
+type loadOptions struct {
+	Tags  []string // Build tags
+	Tests bool     // Include the test files
+}
+
+// getPkgAndType loads src (directory/files) and return the *types.Package,
 // *types.Struct for the passed in structName and the directory for the sources
-func getPkgAndType(structName string, src ...string) (*types.Package, *types.Struct, string, error) {
-	pkg, dir, err := parsePkg(src...)
+func getPkgAndType(structName string, l loadOptions, src ...string) (*types.Package, *types.Struct, string, error) {
+	pkgs, dir, err := loadPkgs(l, src...)
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("parsing package from provided sources: %s", err)
+		return nil, nil, "", fmt.Errorf("loading package from provided sources: %s", err)
 	}
 
-	// Check that struct exists in package
-	o := pkg.Scope().Lookup(structName)
-	if o == nil {
-		return nil, nil, "", fmt.Errorf("the struct %s doesn't seem to exists in package %s", structName, pkg.Name())
+	// Check that struct exists in package. With tests, the package is loaded
+	// with and without the test files, so the variant with the most files is
+	// used
+	var (
+		pkg   *packages.Package
+		found types.Object
+	)
+	for _, p := range pkgs {
+		if strings.HasSuffix(p.ID, ".test") {
+			// Generated test main package
+			continue
+		}
+		o := p.Types.Scope().Lookup(structName)
+		if o == nil {
+			continue
+		}
+		if pkg == nil || len(p.GoFiles) > len(pkg.GoFiles) {
+			pkg, found = p, o
+		}
+	}
+	if found == nil {
+		return nil, nil, "", fmt.Errorf("the struct %s doesn't seem to exists in package %s", structName, pkgs[0].Name)
 	}
 	// Check that it really is of type struct
-	t, ok := o.Type().Underlying().(*types.Struct)
+	t, ok := found.Type().Underlying().(*types.Struct)
 	if !ok {
 		return nil, nil, "", fmt.Errorf("the type %s is not a struct", structName)
 	}
 
-	return pkg, t, dir, nil
+	return pkg.Types, t, dir, nil
 }
 
-// parsePkg parses the directory or files for Go code and
-// do type-checks on it. It will return the types.Package and
-// directory location if successful
-func parsePkg(src ...string) (*types.Package, string, error) {
+// loadPkgs loads and type-checks the package in the directory, or the package
+// made of the files, the same way as the go command builds it. It will return
+// the packages and directory location if successful
+func loadPkgs(l loadOptions, src ...string) ([]*packages.Package, string, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Tests: l.Tests,
+	}
+	if len(l.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(l.Tags, ",")}
+	}
+
 	var (
-		dir       string
-		fileNames []string
+		dir      string
+		patterns []string
 	)
 	if len(src) == 1 && isDirectory(src[0]) {
 		dir = src[0]
-		pkg, err := build.Default.ImportDir(dir, 0)
-		if err != nil {
-			return nil, dir, fmt.Errorf("cannot process directory %s: %s", dir, err)
-		}
-		fileNames = append(fileNames, pkg.GoFiles...)
-		fileNames = append(fileNames, pkg.CgoFiles...)
-		fileNames = append(fileNames, pkg.SFiles...)
-		fileNames = prefixDirectory(dir, fileNames)
+		cfg.Dir = dir
+		patterns = []string{"."}
 	} else {
 		dir = filepath.Dir(src[0])
-		fileNames = src
-	}
-
-	var (
-		astFiles []*ast.File
-		pkgName  string
-	)
-	fset := token.NewFileSet()
-	for _, fileName := range fileNames {
-		if !strings.HasSuffix(fileName, ".go") {
-			continue
+		cfg.Dir = dir
+		for _, fileName := range src {
+			if !strings.HasSuffix(fileName, ".go") {
+				continue
+			}
+			abs, err := filepath.Abs(fileName)
+			if err != nil {
+				return nil, dir, err
+			}
+			patterns = append(patterns, abs)
 		}
-		parsedFile, err := parser.ParseFile(fset, fileName, nil, 0)
-		if err != nil {
-			return nil, dir, fmt.Errorf("parsing package: %s: %s", fileName, err)
+		if len(patterns) == 0 {
+			return nil, dir, fmt.Errorf("%s: no buildable Go files", dir)
 		}
-		astFiles = append(astFiles, parsedFile)
 	}
-	if len(astFiles) == 0 {
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, dir, fmt.Errorf("cannot process %s: %s", dir, err)
+	}
+	if len(pkgs) == 0 {
 		return nil, dir, fmt.Errorf("%s: no buildable Go files", dir)
 	}
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			return nil, dir, fmt.Errorf("type-checking package %s: %s", p.Name, p.Errors[0])
+		}
+	}
 
-	pkgName = astFiles[0].Name.Name
-	conf := types.Config{Importer: importer.Default()}
-	pkg, err := conf.Check(pkgName, fset, astFiles, nil)
-	if err != nil {
-		return nil, dir, fmt.Errorf("type-checking package %s: %s", pkgName, err)
+	return pkgs, dir, nil
+}
+
+// outputPkgName returns the name of the package in the directory dir, or the
+// name of the directory if it doesn't contain a package yet
+func outputPkgName(dir string) string {
+	cfg := &packages.Config{
+		Mode: packages.NeedName,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err == nil && len(pkgs) > 0 && pkgs[0].Name != "" {
+		return pkgs[0].Name
 	}
 
-	return pkg, dir, nil
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return filepath.Base(abs)
 }
 
 // isDirectory reports whether the named file is a directory.
@@ -96,15 +142,3 @@ func isDirectory(name string) bool {
 	}
 	return info.IsDir()
 }
-
-// prefixDirectory joins the directoy with each filename
-func prefixDirectory(directory string, fileNames []string) []string {
-	if directory == "." {
-		return fileNames
-	}
-	ret := make([]string, len(fileNames))
-	for i, fileName := range fileNames {
-		ret[i] = filepath.Join(directory, fileName)
-	}
-	return ret
-}
@@ -3,20 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pengux/cruder/generator"
 	"github.com/spf13/cobra"
 )
 
-// var cfgFile string
 var (
-	pkgName         string
-	funcs           []string
-	skipFuncSuffix  bool
-	readFields      []string
-	writeFields     []string
-	primaryField    string
-	softDeleteField string
+	opts  generator.Options
+	load  loadOptions
+	funcs []string
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -25,12 +21,26 @@ var RootCmd = &cobra.Command{
 	Short: "Generate code for CRUD functions from a Go struct",
 	Long: `cruder is a tool to generate code for Create, Read, Update, Delete functions
 from a Go struct. It supports multiple generators which are listed in the 'Available
-Commands section'. Functions that can be generated are:
+Commands' section, the generate command generates the code for multiple structs
+listed in a config file. Functions that can be generated are:
 - Create: Adds an entry
 - Read: Gets an entry using an ID
 - List: Gets multiple entries
 - Update: Updates an entry
 - Delete: Deletes an entry using an ID
+- Upsert: Adds an entry or updates it if it already exists and is not soft deleted (pg only)
+- CreateMany: Adds multiple entries (pg only)
+- GetMany: Gets multiple entries using their IDs (pg only)
+- Count: Counts the entries matching a filter (pg only)
+- Exists: Checks if an entry exists using an ID (pg only)
+- ListAfter: Gets multiple entries using cursor pagination (pg only)
+- Sorter: A typed sort order to pass to List (pg only)
+- Filter: A typed filter builder to pass to List and Count (pg only)
+- Restore: Restores a soft deleted entry using an ID (pg only)
+- HardDelete: Deletes an entry using an ID, bypassing soft deletion (pg only)
+- ListDeleted: Gets multiple soft deleted entries (pg only)
+- Purge: Deletes the entries soft deleted before a time (pg only)
+- Patch: Updates the fields of an entry which are set in a patch (pg only)
 `,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
@@ -39,7 +49,17 @@ Commands section'. Functions that can be generated are:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// A subcommand is added for every backend registered in the generator package,
+// backends are registered by importing their packages.
 func Execute() {
+	for _, b := range generator.Backends() {
+		RootCmd.AddCommand(newBackendCmd(b))
+	}
+	RootCmd.AddCommand(newGenerateCmd())
+
+	// Shown in the header of the generated files
+	opts.CommandLine = strings.Join(os.Args[1:], " ")
+
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -47,18 +67,7 @@ func Execute() {
 }
 
 func init() {
-	// cobra.OnInitialize(initConfig)
-	//
-	// // Here you will define your flags and configuration settings.
-	// // Cobra supports persistent flags, which, if defined here,
-	// // will be global for your application.
-	// RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cobra.yaml)")
-	//
-	// // Cobra also supports local flags, which will only run
-	// // when this action is called directly.
-	// RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-
-	RootCmd.PersistentFlags().StringVar(&pkgName, "pkg", "", "package name for the generated code, default to the same package from input")
+	RootCmd.PersistentFlags().StringVar(&opts.PkgName, "pkg", "", "package name for the generated code, default to the same package from input, or to the package of the output directory if it is another directory")
 	RootCmd.PersistentFlags().StringSliceVar(&funcs, "fn", []string{
 		string(generator.Create),
 		string(generator.Get),
@@ -66,10 +75,13 @@ func init() {
 		string(generator.Update),
 		string(generator.Delete),
 	}, `CRUD functions to generate, e.g. --fn "create" --fn "delete". Default to all functions`)
-	RootCmd.PersistentFlags().BoolVar(&skipFuncSuffix, "skipsuffix", false, "Skip adding the struct name as suffix to the generated functions")
-	RootCmd.PersistentFlags().StringVar(&primaryField, "primaryfield", "", "the field to use as primary key. Default to 'ID' if it exists in the <struct>")
-	RootCmd.PersistentFlags().StringVar(&softDeleteField, "softdeletefield", "", "the field to use for softdelete (should be of type nullable datetime field). Default to 'DeletedAt' if it exists in the <struct>")
-	RootCmd.PersistentFlags().StringSliceVar(&readFields, "readfields", []string{}, "Fields in the struct that should be used for read operations (get,list). Default to all fields except the one used for softdelete")
-	RootCmd.PersistentFlags().StringSliceVar(&writeFields, "writefields", []string{}, "Fields in the struct that should be used for write operations (create,update). Default to all fields")
+	RootCmd.PersistentFlags().BoolVar(&opts.SkipSuffix, "skipsuffix", false, "Skip adding the struct name as suffix to the generated functions")
+	RootCmd.PersistentFlags().BoolVar(&opts.NoContext, "nocontext", false, "Generate functions without a context.Context parameter, using the database/sql methods without context")
+	RootCmd.PersistentFlags().StringSliceVar(&opts.PrimaryFields, "primaryfield", []string{}, "the fields to use as primary key, multiple fields make a composite key (pg only). Default to fields tagged with `cruder:\"pk\"` or 'ID' if it exists in the <struct>")
+	RootCmd.PersistentFlags().StringVar(&opts.SoftDeleteField, "softdeletefield", "", "the field to use for softdelete, of type nullable datetime (pg also supports bool and status string fields, see --softdeletestrategy). Default to 'DeletedAt' if it exists in the <struct>")
+	RootCmd.PersistentFlags().StringSliceVar(&opts.ReadFields, "readfields", []string{}, "Fields in the struct that should be used for read operations (get,list). Default to all fields except the one used for softdelete")
+	RootCmd.PersistentFlags().StringSliceVar(&load.Tags, "tags", []string{}, "build tags used to load the package of the <struct>")
+	RootCmd.PersistentFlags().BoolVar(&load.Tests, "tests", false, "Include the test files when loading the package of the <struct>")
+	RootCmd.PersistentFlags().StringSliceVar(&opts.WriteFields, "writefields", []string{}, "Fields in the struct that should be used for write operations (create,update). Default to all fields")
 
 }
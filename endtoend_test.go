@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Used by the tests in testdata, imported here so it is a dependency
+	// of the module
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The end to end tests generate the code for the struct Foo of a model, then
+// run it with the foo_test.go of a directory in testdata, which fails if the
+// generated functions don't behave as expected. The tests use SQLite through
+// github.com/mattn/go-sqlite3 and the openDB of testdata/db_test.go, which
+// adds NOW(). The backends are run against it, as SQLite supports the
+// placeholders, quoting, ON CONFLICT and RETURNING of their queries
+var endToEndTests = []struct {
+	dir   string   // Directory in testdata
+	model string   // File declaring Foo, default to model.go in the directory, or a package directory to generate from into another package
+	args  []string // Arguments of cruder, the struct Foo and the model are appended
+}{
+	{"generate", "", []string{"generate"}},
+	{"sqlite", "", []string{"sqlite", "--table", "foo", "--nocontext"}},
+	{"sqliteexample", "example/example.go", []string{"sqlite", "--table", "foo"}},
+	{"mysql", "", []string{"mysql", "--table", "foo"}},
+	{"pgupsert", "", []string{"pg", "--table", "foo", "--fn", "upsert,delete"}},
+	{"pgcreatemany", "", []string{"pg", "--table", "foo", "--fn", "createmany"}},
+	{"pggetmany", "", []string{"pg", "--table", "foo", "--getmanymap", "--fn", "getmany"}},
+	{"pgcount", "", []string{"pg", "--table", "foo", "--fn", "list,count,exists"}},
+	{"pglistafter", "", []string{"pg", "--table", "foo", "--cursorfield", "Rank", "--fn", "listafter"}},
+	{"pgsorter", "", []string{"pg", "--table", "foo", "--fn", "list,sorter"}},
+	{"pgfilter", "", []string{"pg", "--table", "foo", "--fn", "list,count,filter,delete,listdeleted,listafter"}},
+	{"pgcomposite", "", []string{"pg", "--table", "foo", "--fn", "create,createmany,get,update,delete,exists"}},
+	{"pgparamnames", "", []string{"pg", "--table", "foo", "--fn", "create,delete,exists"}},
+	{"pgtypedpk", "", []string{"pg", "--table", "foo", "--fn", "get,delete"}},
+	{"pgsoftdelete", "", []string{"pg", "--table", "foo", "--fn", "get,list,delete,restore,harddelete,listdeleted,purge"}},
+	{"pgstatus", "", []string{"pg", "--table", "foo", "--softdeletefield", "Status", "--softdeletevalue", "archived", "--fn", "get,list,count,delete"}},
+	{"pgbool", "", []string{"pg", "--table", "foo", "--softdeletefield", "IsDeleted", "--fn", "get,list,count,delete,restore"}},
+	{"pgnullable", "", []string{"pg", "--table", "foo", "--softdeletefield", "IsDeleted", "--fn", "get,list,count,delete"}},
+	{"pgnullable", "", []string{"pg", "--table", "foo", "--softdeletefield", "Removed", "--fn", "get,list,count,delete"}},
+	{"pgnullable", "", []string{"pg", "--table", "foo", "--softdeletefield", "Status", "--fn", "get,list,count,delete"}},
+	{"pgnullable", "", []string{"pg", "--table", "foo", "--softdeletefield", "State", "--fn", "get,list,count,delete"}},
+	{"pgtimestamps", "", []string{"pg", "--table", "foo", "--fn", "create,update"}},
+	{"pgversion", "", []string{"pg", "--table", "foo", "--fn", "get,update,patch,delete,exists"}},
+	{"sqlitetags", "", []string{"sqlite", "--table", "foo", "--nocontext"}},
+	{"pgtags", "", []string{"pg", "--table", "foo", "--fn", "create,get,list,filter"}},
+	{"pgexternal", "testdata/pgexternal/models", []string{"pg", "--table", "foo", "--fn", "create,get,list"}},
+	{"pgrepository", "", []string{"pg", "--table", "foo", "--repository", "--fn", "create,get,list,update,delete,exists"}},
+}
+
+func TestEndToEnd(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping end to end test in short mode")
+	}
+
+	tmp, err := ioutil.TempDir("", "cruder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	cruder := buildCruder(t, tmp)
+	for _, tt := range endToEndTests {
+		t.Run(tt.dir, func(t *testing.T) {
+			// The tests are run from a directory in testdata, so they use the
+			// dependencies of this module
+			dir, err := ioutil.TempDir("testdata", "run")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			model := tt.model
+			if model == "" {
+				model = filepath.Join("testdata", tt.dir, "model.go")
+			}
+			src := filepath.Join(dir, "model.go")
+			if info, err := os.Stat(model); err == nil && info.IsDir() {
+				src = model
+			} else {
+				copyFile(t, model, src)
+			}
+			copyFile(t, filepath.Join("testdata", tt.dir, "foo_test.go"), filepath.Join(dir, "foo_test.go"))
+			copyFile(t, filepath.Join("testdata", "db_test.go"), filepath.Join(dir, "db_test.go"))
+
+			args := append(tt.args, "-o", filepath.Join(dir, "foo_crud.go"), "Foo", src)
+			// The generate command reads the structs from cruder.yaml instead
+			if tt.args[0] == "generate" {
+				copyFile(t, filepath.Join("testdata", tt.dir, "cruder.yaml"), filepath.Join(dir, "cruder.yaml"))
+				args = append(tt.args, "-c", filepath.Join(dir, "cruder.yaml"))
+			}
+			if out, err := exec.Command(cruder, args...).CombinedOutput(); err != nil {
+				t.Fatalf("running cruder %v: %s\n%s", args, err, out)
+			}
+
+			if out, err := exec.Command("go", "test", "./"+dir).CombinedOutput(); err != nil {
+				t.Fatalf("running the generated code: %s\n%s", err, out)
+			}
+		})
+	}
+}
+
+// The mysql and sqlite backends fail on the options of the cruder tag which
+// only pg supports, instead of ignoring them
+func TestRejectedTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping end to end test in short mode")
+	}
+
+	tmp, err := ioutil.TempDir("", "cruder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	cruder := buildCruder(t, tmp)
+	model := filepath.Join(tmp, "model.go")
+	for _, backend := range []string{"mysql", "sqlite"} {
+		for _, option := range []string{"type=jsonb", "omitempty", "version", "conflict"} {
+			src := fmt.Sprintf("package main\n\ntype Foo struct {\n\tID int64\n\tName string `cruder:%q`\n}\n", option)
+			if err := ioutil.WriteFile(model, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			args := []string{backend, "-o", filepath.Join(tmp, "foo_crud.go"), "Foo", model}
+			out, err := exec.Command(cruder, args...).CombinedOutput()
+			if err == nil || !strings.Contains(string(out), "not supported") {
+				t.Fatalf("running cruder %v: got %v, want an error for %s\n%s", args, err, option, out)
+			}
+		}
+	}
+}
+
+// buildCruder builds the cruder binary into dir and returns its path
+func buildCruder(t *testing.T, dir string) string {
+	cruder := filepath.Join(dir, "cruder")
+	if out, err := exec.Command("go", "build", "-o", cruder, ".").CombinedOutput(); err != nil {
+		t.Fatalf("building cruder: %s\n%s", err, out)
+	}
+
+	return cruder
+}
+
+func copyFile(t *testing.T, from, to string) {
+	b, err := ioutil.ReadFile(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(to, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
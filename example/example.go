@@ -25,12 +25,12 @@ func main() {
 	// foo := Foo{
 	// 	Name: "Test",
 	// }
-	// createdFoo, err := CreateFoo(db, foo)
+	// createdFoo, err := CreateFoo(context.Background(), db, foo)
 	// if err != nil {
 	// 	log.Fatal(err)
 	// }
 	// log.Println(createdFoo)
 	//
-	// foos, err := ListFoo(db, 0, 0, nil, nil)
+	// foos, err := ListFoos(context.Background(), db, 0, 0, nil, nil)
 	// log.Println(foos)
 }
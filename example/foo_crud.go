@@ -1,38 +1,20 @@
 // Package main contains CRUD methods that are generated by `cruder`
-// Code generated by "cruder pg Foo ./example/example.go"; DO NOT EDIT
+// Code generated by "cruder pg -o example/foo_crud.go Foo ./example/example.go"; DO NOT EDIT
 package main
 
 import (
-	"database/sql"
+	"context"
 	"errors"
 	"fmt"
+	uuid "github.com/satori/go.uuid"
 	"strings"
 )
 
-type cruderQueryRower interface {
-	QueryRow(string, ...interface{}) *sql.Row
-}
-
-type cruderQueryer interface {
-	Query(string, ...interface{}) (*sql.Rows, error)
-}
-
-type cruderSQLFilter interface {
-	Where() (string, []interface{})
-}
-
-type cruderSQLSorter interface {
-	OrderBy() string
-}
-
-type cruderExecer interface {
-	Exec(string, ...interface{}) (sql.Result, error)
-}
-
 // CreateFoo inserts an entry into DB
-func CreateFoo(db cruderQueryRower, x Foo) (*Foo, error) {
+func CreateFoo(ctx context.Context, db cruderQueryRower, x Foo) (*Foo, error) {
 	var y Foo
-	err := db.QueryRow(
+	err := db.QueryRowContext(
+		ctx,
 		`INSERT INTO Foo (name) VALUES ($1)
 		RETURNING id, name`,
 		x.Name,
@@ -42,9 +24,10 @@ func CreateFoo(db cruderQueryRower, x Foo) (*Foo, error) {
 }
 
 // GetFoo returns a single entry from DB based on primary key
-func GetFoo(db cruderQueryRower, id interface{}) (*Foo, error) {
+func GetFoo(ctx context.Context, db cruderQueryRower, id uuid.UUID) (*Foo, error) {
 	var y Foo
-	err := db.QueryRow(
+	err := db.QueryRowContext(
+		ctx,
 		`SELECT id, name FROM Foo WHERE id = $1 AND deleted_at IS NULL`,
 		id,
 	).Scan(&y.ID, &y.Name)
@@ -52,15 +35,15 @@ func GetFoo(db cruderQueryRower, id interface{}) (*Foo, error) {
 	return &y, err
 }
 
-// ListFoo returns a list of entries from DB based on passed in limit, offset, filters and sorting
-func ListFoo(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter, sorter cruderSQLSorter) ([]Foo, error) {
+// ListFoos returns a list of entries from DB based on passed in limit, offset, filters and sorting
+func ListFoos(ctx context.Context, db cruderQueryer, limit, offset uint64, filter cruderSQLFilter, sorter cruderSQLSorter) ([]Foo, error) {
 	var args []interface{}
 	sqlParts := []string{`SELECT id, name FROM Foo`}
 
 	sqlParts = append(sqlParts, "WHERE deleted_at IS NULL")
 	if filter != nil {
 		if filters, filterArgs := filter.Where(); filters != "" {
-			sqlParts = append(sqlParts, " AND "+filters)
+			sqlParts = append(sqlParts, " AND "+"("+filters+")")
 			args = append(args, filterArgs...)
 		}
 	}
@@ -77,7 +60,8 @@ func ListFoo(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter, sor
 	if offset > 0 {
 		sqlParts = append(sqlParts, fmt.Sprintf("OFFSET %d", offset))
 	}
-	rows, err := db.Query(
+	rows, err := db.QueryContext(
+		ctx,
 		strings.Join(sqlParts, " "),
 		args...,
 	)
@@ -89,20 +73,21 @@ func ListFoo(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter, sor
 	r := []Foo{}
 	for rows.Next() {
 		var e Foo
-		if err := rows.Scan(id, name); err != nil {
+		if err := rows.Scan(&e.ID, &e.Name); err != nil {
 			return nil, err
 		}
 		r = append(r, e)
 	}
 
-	return r, err
+	return r, rows.Err()
 }
 
 // UpdateFoo updates an entry into DB
-func UpdateFoo(db cruderQueryRower, x Foo) (*Foo, error) {
+func UpdateFoo(ctx context.Context, db cruderQueryRower, x Foo) (*Foo, error) {
 	var y Foo
-	err := db.QueryRow(
-		`UPDATE Foo SET name = $1 WHERE id = $1 AND deleted_at IS NULL
+	err := db.QueryRowContext(
+		ctx,
+		`UPDATE Foo SET name = $1 WHERE id = $2 AND deleted_at IS NULL
 		RETURNING id, name`,
 		x.Name, x.ID,
 	).Scan(&y.ID, &y.Name)
@@ -111,8 +96,9 @@ func UpdateFoo(db cruderQueryRower, x Foo) (*Foo, error) {
 }
 
 // DeleteFoo deletes an entry from DB
-func DeleteFoo(db cruderExecer, id interface{}) error {
-	result, err := db.Exec(
+func DeleteFoo(ctx context.Context, db cruderExecer, id uuid.UUID) error {
+	result, err := db.ExecContext(
+		ctx,
 		`UPDATE Foo SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
 		id,
 	)
@@ -0,0 +1,682 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	defaultPrimaryFieldName    = "ID"
+	defaultSoftDeleteFieldName = "DeletedAt"
+	defaultCreatedAtFieldName  = "CreatedAt"
+	defaultUpdatedAtFieldName  = "UpdatedAt"
+)
+
+type (
+	// Dialect contains the parts of the SQL which differ between the backends
+	// and are needed by Base
+	Dialect interface {
+		// QuoteIdentifier returns the name of a table or a column as written
+		// in queries
+		QuoteIdentifier(name string) string
+		// Placeholder returns the placeholder of the nth argument of a query,
+		// starting at 1
+		Placeholder(n int) string
+		// Now returns the expression of the current time
+		Now() string
+	}
+
+	// Base contains the state and the helpers shared by the backends, which
+	// embed it and add the SQL of their functions
+	Base struct {
+		Pkg        *types.Package
+		Struct     *types.Struct
+		StructName string
+		Dialect    Dialect
+
+		TableName   string
+		PkgName     string
+		ExternalPkg bool   // Generate into another package than Pkg
+		CommandLine string // Shown in the header of the generated file
+		SkipSuffix  bool
+		NoContext   bool
+		// NoAutoTimestamps takes the values of the timestamp fields from the
+		// struct instead of setting them to the current time
+		NoAutoTimestamps bool
+
+		ReadFields            map[int]string
+		WriteFields           map[int]string
+		PrimaryFields         map[int]string
+		CreatedAtFields       map[int]string
+		UpdatedAtFields       map[int]string
+		SoftDeleteFieldOffset int
+
+		header, body bytes.Buffer // Accumulated output.
+		mx           sync.Mutex
+		imports      map[string]string // Import path to package name, if it needs to be named
+	}
+)
+
+// NewBase returns a Base for the struct t named structName in pkg. The fields
+// of the struct can be configured with a comma separated list of options in a
+// "cruder" struct tag, e.g. `cruder:"readonly"`. The options understood by all
+// backends are:
+//
+//	pk          the field is used as primary key
+//	softdelete  the field is used for soft deletion
+//	readonly    the field is only read, never written
+//	writeonly   the field is only written, never read
+//	-           the field is skipped
+//	autocreate  the field is set to the current time on insert
+//	autoupdate  the field is set to the current time on insert and update
+func NewBase(pkg *types.Package, t *types.Struct, structName string, d Dialect) *Base {
+	b := &Base{
+		Pkg:                   pkg,
+		Struct:                t,
+		StructName:            structName,
+		Dialect:               d,
+		TableName:             structName,
+		PkgName:               pkg.Name(),
+		ReadFields:            make(map[int]string, t.NumFields()),
+		WriteFields:           make(map[int]string, t.NumFields()),
+		PrimaryFields:         make(map[int]string),
+		CreatedAtFields:       make(map[int]string),
+		UpdatedAtFields:       make(map[int]string),
+		SoftDeleteFieldOffset: -1, // -1 disable soft deletion
+		imports:               make(map[string]string),
+	}
+
+	// Fields tagged with `cruder:"pk"` are used as primary key, otherwise the
+	// defaultPrimaryFieldName if it exists in the struct
+	for i := 0; i < t.NumFields(); i++ {
+		if b.FieldHasTag(i, "pk") {
+			b.PrimaryFields[i] = t.Field(i).Name()
+		}
+	}
+	if len(b.PrimaryFields) == 0 {
+		for i := 0; i < t.NumFields(); i++ {
+			if defaultPrimaryFieldName == t.Field(i).Name() && !b.FieldHasTag(i, "-") {
+				b.PrimaryFields[i] = t.Field(i).Name()
+			}
+		}
+	}
+
+	// Fields tagged with `cruder:"autocreate"` or `cruder:"autoupdate"` are set
+	// to the current time by the database, otherwise the
+	// defaultCreatedAtFieldName and defaultUpdatedAtFieldName if they exist in
+	// the struct
+	for i := 0; i < t.NumFields(); i++ {
+		if b.FieldHasTag(i, "autocreate") {
+			b.CreatedAtFields[i] = t.Field(i).Name()
+		}
+		if b.FieldHasTag(i, "autoupdate") {
+			b.UpdatedAtFields[i] = t.Field(i).Name()
+		}
+	}
+	for i := 0; i < t.NumFields(); i++ {
+		if b.FieldHasTag(i, "-") {
+			continue
+		}
+		if len(b.CreatedAtFields) == 0 && defaultCreatedAtFieldName == t.Field(i).Name() {
+			b.CreatedAtFields[i] = t.Field(i).Name()
+		}
+		if len(b.UpdatedAtFields) == 0 && defaultUpdatedAtFieldName == t.Field(i).Name() {
+			b.UpdatedAtFields[i] = t.Field(i).Name()
+		}
+	}
+
+	// The field tagged with `cruder:"softdelete"` is used for soft deletion,
+	// otherwise the defaultSoftDeleteFieldName if it exists in the struct
+	for i := 0; i < t.NumFields(); i++ {
+		if b.FieldHasTag(i, "softdelete") {
+			b.SoftDeleteFieldOffset = i
+		}
+	}
+	if b.SoftDeleteFieldOffset == -1 {
+		for i := 0; i < t.NumFields(); i++ {
+			if defaultSoftDeleteFieldName == t.Field(i).Name() && !b.FieldHasTag(i, "-") {
+				b.SoftDeleteFieldOffset = i
+			}
+		}
+	}
+
+	for i := 0; i < t.NumFields(); i++ {
+		if b.SkipField(i) {
+			continue
+		}
+
+		// Fields tagged with `cruder:"writeonly"` are not included in ReadFields
+		if !b.FieldHasTag(i, "writeonly") {
+			b.ReadFields[i] = t.Field(i).Name()
+		}
+
+		// Don't include the primary fields and the fields tagged with
+		// `cruder:"readonly"` in WriteFields
+		if _, ok := b.PrimaryFields[i]; ok || b.FieldHasTag(i, "readonly") {
+			continue
+		}
+		b.WriteFields[i] = t.Field(i).Name()
+	}
+
+	return b
+}
+
+// SkipField reports whether the field is left out of ReadFields and
+// WriteFields by default, which are the fields tagged with `cruder:"-"` and
+// the soft delete field
+func (b *Base) SkipField(i int) bool {
+	return b.FieldHasTag(i, "-") || i == b.SoftDeleteFieldOffset
+}
+
+// SetOptions applies the settings shared by all backends
+func (b *Base) SetOptions(opts Options) error {
+	if len(opts.PkgName) > 0 {
+		b.PkgName = opts.PkgName
+	}
+	b.ExternalPkg = opts.ExternalPkg
+	b.CommandLine = opts.CommandLine
+	b.SkipSuffix = opts.SkipSuffix
+	b.NoContext = opts.NoContext
+
+	if len(opts.ReadFields) > 0 {
+		if err := b.SetReadFields(opts.ReadFields); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.WriteFields) > 0 {
+		if err := b.SetWriteFields(opts.WriteFields); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.PrimaryFields) > 0 {
+		if err := b.SetPrimaryFields(opts.PrimaryFields); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.SoftDeleteField) > 0 {
+		if err := b.SetSoftDeleteField(opts.SoftDeleteField); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetReadFields sets the fields that should be returned in reading operations.
+// The passed in slice will be match against the fieldnames of the struct
+func (b *Base) SetReadFields(fields []string) error {
+	fieldNames, err := b.MatchFields(fields)
+	if err != nil {
+		return err
+	}
+
+	b.ReadFields = fieldNames
+	return nil
+}
+
+// SetWriteFields sets the fields that should be returned in writing operations.
+// The passed in slice will be match against the fieldnames of the struct
+func (b *Base) SetWriteFields(fields []string) error {
+	fieldNames, err := b.MatchFields(fields)
+	if err != nil {
+		return err
+	}
+
+	b.WriteFields = fieldNames
+	return nil
+}
+
+// SetPrimaryField sets the field that are used as primary key in lookups
+func (b *Base) SetPrimaryField(f string) error {
+	return b.SetPrimaryFields([]string{f})
+}
+
+// SetPrimaryFields sets the fields that are used as composite primary key in lookups
+func (b *Base) SetPrimaryFields(fields []string) error {
+	fieldNames, err := b.MatchFields(fields)
+	if err != nil {
+		return err
+	}
+
+	b.PrimaryFields = fieldNames
+	return nil
+}
+
+// SetSoftDeleteField sets the field that should be used for soft deletion.
+// The field should be of type nullable datetime, or a bool or status string
+// with the pg backend, but this function does not check that.
+func (b *Base) SetSoftDeleteField(f string) error {
+	i, err := b.FieldOffset(f)
+	if err != nil {
+		return err
+	}
+
+	b.SoftDeleteFieldOffset = i
+	return nil
+}
+
+// MatchFields returns the offsets and names of the passed in fields
+func (b *Base) MatchFields(fields []string) (map[int]string, error) {
+	fieldNames := make(map[int]string)
+	for _, f := range fields {
+		i, err := b.FieldOffset(f)
+		if err != nil {
+			return nil, err
+		}
+		fieldNames[i] = b.Struct.Field(i).Name()
+	}
+
+	return fieldNames, nil
+}
+
+// FieldOffset returns the offset of the field with the name f in the struct
+func (b *Base) FieldOffset(f string) (int, error) {
+	for i := 0; i < b.Struct.NumFields(); i++ {
+		if strings.TrimSpace(f) == b.Struct.Field(i).Name() {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("the field %s does not exists in struct %s", f, b.StructName)
+}
+
+// RequirePrimaryField returns an error if no field is used as primary key
+func (b *Base) RequirePrimaryField() error {
+	if len(b.PrimaryFields) == 0 {
+		return fmt.Errorf("no primary field is set for struct %s", b.StructName)
+	}
+
+	return nil
+}
+
+// RequireSinglePrimaryField returns an error unless exactly one field is used
+// as primary key, for backends which don't support composite primary keys
+func (b *Base) RequireSinglePrimaryField() error {
+	if err := b.RequirePrimaryField(); err != nil {
+		return err
+	}
+	if len(b.PrimaryFields) > 1 {
+		return errors.New("composite primary keys are not supported")
+	}
+
+	return nil
+}
+
+// RequireSoftDeleteField returns an error if no soft delete field is set, for
+// functions which only make sense with soft deletion
+func (b *Base) RequireSoftDeleteField() error {
+	if b.SoftDeleteFieldOffset == -1 {
+		return fmt.Errorf("no soft delete field is set for struct %s", b.StructName)
+	}
+
+	return nil
+}
+
+// PrimaryFieldOffset returns the offset of the primary field, for backends
+// which don't support composite primary keys, see RequireSinglePrimaryField
+func (b *Base) PrimaryFieldOffset() int {
+	for k := range b.PrimaryFields {
+		return k
+	}
+
+	return -1
+}
+
+// SortedOffsets returns the offsets of the fields in the order they appear in the struct
+func SortedOffsets(fields map[int]string) []int {
+	var keys []int
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
+
+// ReadFieldNames returns a slice of the read field names.
+// A prefix can be passed which would be added before each name.
+func (b *Base) ReadFieldNames(prefix string) []string {
+	return b.FieldNames(b.ReadFields, prefix)
+}
+
+// ReadFieldDBNames returns a slice of the read field names, but in their DB forms (if any).
+// The DB form is taken from the "db" struct tag if defined, or it will be the same as the field
+// name. A prefix can be passed which would be added before each name.
+func (b *Base) ReadFieldDBNames(prefix string) []string {
+	return b.FieldDBNames(b.ReadFields, prefix)
+}
+
+// WriteFieldNames returns a slice of the write field names as arguments for a query,
+// except the timestamp fields set by the database.
+// A prefix can be passed which would be added before each name.
+func (b *Base) WriteFieldNames(prefix string) []string {
+	return b.FieldArgs(b.ValueFields(b.WriteFields), prefix)
+}
+
+// WriteFieldDBNames returns a slice of the write field names, but in their DB forms (if any),
+// except the timestamp fields set by the database.
+// The DB form is taken from the "db" struct tag if defined, or it will be the same as the field
+// name. A prefix can be passed which would be added before each name.
+func (b *Base) WriteFieldDBNames(prefix string) []string {
+	return b.FieldDBNames(b.ValueFields(b.WriteFields), prefix)
+}
+
+// FieldNames returns a slice of the names of the fields, ordered as in the struct.
+// A prefix can be passed which would be added before each name.
+func (b *Base) FieldNames(fields map[int]string, prefix string) []string {
+	var fieldNames []string
+	for _, k := range SortedOffsets(fields) {
+		fieldNames = append(fieldNames, prefix+fields[k])
+	}
+
+	return fieldNames
+}
+
+// FieldDBNames returns a slice of the DB names of the fields, ordered as in the struct.
+// A prefix can be passed which would be added before each name.
+func (b *Base) FieldDBNames(fields map[int]string, prefix string) []string {
+	var fieldNames []string
+	for _, k := range SortedOffsets(fields) {
+		fieldNames = append(fieldNames, prefix+b.FieldDBName(k))
+	}
+
+	return fieldNames
+}
+
+// FieldArgs returns a slice of expressions for the fields that can be passed
+// as arguments to a query, ordered as in the struct. A prefix can be passed
+// which would be added before each name.
+func (b *Base) FieldArgs(fields map[int]string, prefix string) []string {
+	var args []string
+	for _, k := range SortedOffsets(fields) {
+		name := prefix + fields[k]
+
+		// If the field is a struct, then return a pointer expression
+		if _, ok := b.Struct.Field(k).Type().(*types.Named); ok {
+			name = "&" + name
+		}
+		args = append(args, name)
+	}
+
+	return args
+}
+
+// PlaceholderStrings returns a slice of strings for n placeholders
+func (b *Base) PlaceholderStrings(n int) []string {
+	var placeholders []string
+	for i := 1; i <= n; i++ {
+		placeholders = append(placeholders, b.Dialect.Placeholder(i))
+	}
+
+	return placeholders
+}
+
+// CtxParam returns the context parameter of the generated functions, which is
+// empty if NoContext is set
+func (b *Base) CtxParam() string {
+	if b.NoContext {
+		return ""
+	}
+
+	b.AddImport("context")
+	return "ctx context.Context, "
+}
+
+// CtxArg returns the context argument passed to the database/sql methods, on
+// its own line
+func (b *Base) CtxArg() string {
+	if b.NoContext {
+		return ""
+	}
+
+	return "ctx,\n"
+}
+
+// DBMethod returns the name of the database/sql method to call, which is the
+// Context variant unless NoContext is set
+func (b *Base) DBMethod(name string) string {
+	if b.NoContext {
+		return name
+	}
+
+	return name + "Context"
+}
+
+// HelperType returns the name of the helper type in the variant matching
+// NoContext. The helper types are declared in TypesFileName, which is shared
+// by the generated files of the package
+func (b *Base) HelperType(name string) string {
+	return HelperType(name, b.NoContext)
+}
+
+// HeaderPrintf writes the input to the header buffer
+func (b *Base) HeaderPrintf(in string, args ...interface{}) {
+	fmt.Fprintf(&b.header, in, args...)
+}
+
+// Printf writes the input to the body buffer
+func (b *Base) Printf(in string, args ...interface{}) {
+	fmt.Fprintf(&b.body, in, args...)
+}
+
+func (b *Base) pkgDecl() []byte {
+	return []byte(fmt.Sprintf("package %s\n", b.PkgName))
+}
+
+func (b *Base) importsDecl() []byte {
+	var imports []string
+	for path, name := range b.imports {
+		if name != "" {
+			path = name + " \"" + path + "\""
+		} else {
+			path = "\"" + path + "\""
+		}
+		imports = append(imports, path)
+	}
+
+	return []byte(fmt.Sprintf(`
+import (
+	%s
+)
+`, strings.Join(imports, "\n\t")))
+}
+
+// Format returns the gofmt-ed contents of the buffers.
+func (b *Base) Format() ([]byte, error) {
+	return format.Source(append(append(b.pkgDecl(), b.importsDecl()...), append(b.header.Bytes(), b.body.Bytes()...)...))
+}
+
+// String output all buffers as string
+func (b *Base) String() string {
+	return string(b.pkgDecl()) + string(b.importsDecl()) + b.header.String() + b.body.String()
+}
+
+// Write writes the formatted file, including the package header, to w
+func (b *Base) Write(w io.Writer) error {
+	out, err := b.Format()
+	if err != nil {
+		return fmt.Errorf("could not format the generated code, try to compile the code to debug: %s\n%s", err, b.String())
+	}
+
+	_, err = io.WriteString(w, Header(b.PkgName, b.CommandLine))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// AddImport adds an import of the package pkg
+func (b *Base) AddImport(pkg string) {
+	b.mx.Lock()
+	if _, ok := b.imports[pkg]; !ok {
+		b.imports[pkg] = ""
+	}
+	b.mx.Unlock()
+}
+
+// AddNamedImport adds an import of the package pkg using name. The name is
+// left out if it's the same as the last element of the import path
+func (b *Base) AddNamedImport(name, pkg string) {
+	if name == path.Base(pkg) {
+		name = ""
+	}
+
+	b.mx.Lock()
+	b.imports[pkg] = name
+	b.mx.Unlock()
+}
+
+// TypeName returns the type as written in the generated code. Types from other
+// packages are qualified with their package name and the packages are imported
+func (b *Base) TypeName(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == b.Pkg && !b.ExternalPkg {
+			return ""
+		}
+
+		b.AddNamedImport(p.Name(), p.Path())
+		return p.Name()
+	})
+}
+
+// StructType returns the struct as written in the generated code, which is
+// qualified with its package name when generating into another package
+func (b *Base) StructType() string {
+	if !b.ExternalPkg {
+		return b.StructName
+	}
+
+	b.AddNamedImport(b.Pkg.Name(), b.Pkg.Path())
+	return b.Pkg.Name() + "." + b.StructName
+}
+
+// CheckExportedFields returns an error if a field which is accessed by the
+// generated code is not exported, as it can't be used from another package.
+// The offsets of fields accessed besides the read, write and primary fields
+// can be passed, -1 is ignored
+func (b *Base) CheckExportedFields(offsets ...int) error {
+	fields := []map[int]string{b.ReadFields, b.WriteFields, b.PrimaryFields}
+	for _, k := range offsets {
+		if k != -1 {
+			fields = append(fields, map[int]string{k: b.Struct.Field(k).Name()})
+		}
+	}
+
+	for _, f := range fields {
+		for _, k := range SortedOffsets(f) {
+			if !b.Struct.Field(k).Exported() {
+				return fmt.Errorf("the field %s of struct %s is not exported and can't be used from another package", b.Struct.Field(k).Name(), b.StructName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TableDBName returns the name of the table as written in queries
+func (b *Base) TableDBName() string {
+	parts := strings.Split(b.TableName, ".")
+	for i, p := range parts {
+		parts[i] = b.Dialect.QuoteIdentifier(p)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// FieldDBName returns the name of the field in its DB form as written in
+// queries. The DB form is taken from the "db" struct tag if defined, otherwise
+// the field name.
+func (b *Base) FieldDBName(i int) string {
+	if i >= b.Struct.NumFields() {
+		return ""
+	}
+
+	st := reflect.StructTag(b.Struct.Tag(i))
+	if st.Get("db") != "" {
+		return b.Dialect.QuoteIdentifier(st.Get("db"))
+	}
+
+	return b.Dialect.QuoteIdentifier(b.Struct.Field(i).Name())
+}
+
+// FieldHasTag reports whether the option is set in the "cruder" struct tag of
+// the field, e.g. `cruder:"conflict"`
+func (b *Base) FieldHasTag(i int, option string) bool {
+	st := reflect.StructTag(b.Struct.Tag(i))
+	for _, o := range strings.Split(st.Get("cruder"), ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FieldTagValue returns the value of a key=value option in the "cruder" struct
+// tag of the field, e.g. jsonb for `cruder:"type=jsonb"`
+func (b *Base) FieldTagValue(i int, key string) string {
+	st := reflect.StructTag(b.Struct.Tag(i))
+	for _, o := range strings.Split(st.Get("cruder"), ",") {
+		if kv := strings.SplitN(strings.TrimSpace(o), "=", 2); len(kv) == 2 && kv[0] == key {
+			return kv[1]
+		}
+	}
+
+	return ""
+}
+
+// RejectTags returns an error if a field is tagged with one of the options,
+// for backends which don't support them. The options with a value, e.g.
+// type=jsonb, are matched by their key
+func (b *Base) RejectTags(options ...string) error {
+	for i := 0; i < b.Struct.NumFields(); i++ {
+		for _, o := range options {
+			if b.FieldHasTag(i, o) || b.FieldTagValue(i, o) != "" {
+				return fmt.Errorf("the cruder tag option %s of the field %s is not supported", o, b.Struct.Field(i).Name())
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParamName returns the field name as a parameter name of generated functions,
+// e.g. ID becomes id and TenantID becomes tenantID
+func ParamName(field string) string {
+	runes := []rune(field)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	// Keep the last upper case letter of an initialism followed by a word,
+	// e.g. URLPath becomes urlPath
+	if i > 1 && i < len(runes) {
+		i--
+	}
+	name := strings.ToLower(string(runes[:i])) + string(runes[i:])
+
+	switch name {
+	case "ctx", "db", "x", "y", "err", "args", "setParts", "where", "patch", "repo", "result", "exists":
+		return name + "Key"
+	}
+	if token.Lookup(name).IsKeyword() {
+		return name + "Key"
+	}
+
+	return name
+}
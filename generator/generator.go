@@ -1,14 +1,30 @@
 package generator
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 // Enum for CRUD functions
 const (
-	Create Function = "create"
-	Get    Function = "get"
-	List   Function = "list"
-	Update Function = "update"
-	Delete Function = "delete"
+	Create      Function = "create"
+	Get         Function = "get"
+	List        Function = "list"
+	Update      Function = "update"
+	Delete      Function = "delete"
+	Upsert      Function = "upsert"
+	CreateMany  Function = "createmany"
+	GetMany     Function = "getmany"
+	Count       Function = "count"
+	Exists      Function = "exists"
+	ListAfter   Function = "listafter"
+	Sorter      Function = "sorter"
+	Filter      Function = "filter"
+	Restore     Function = "restore"
+	HardDelete  Function = "harddelete"
+	ListDeleted Function = "listdeleted"
+	Purge       Function = "purge"
+	Patch       Function = "patch"
 )
 
 type (
@@ -21,3 +37,10 @@ type (
 		Generate(io.Writer, ...Function) error
 	}
 )
+
+// Header returns the comments that should be at the top of every generated
+// file, including the arguments of the command line that was used to generate
+// it
+func Header(pkgName, commandLine string) string {
+	return fmt.Sprintf("// Package %s contains CRUD methods that are generated by `cruder`\n// Code generated by \"cruder %s\"; DO NOT EDIT\n", pkgName, commandLine)
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	createTmpl = `
+// Create%[1]s inserts an entry into DB
+func Create%[1]s(%[9]sdb %[13]s, x %[2]s) (*%[2]s, error) {
+	%[3]s, err := db.%[10]s(
+		%[12]s%[4]s,
+		%[5]s,
+	)
+	if err != nil {
+		return nil, err
+	}
+%[6]s
+	var y %[2]s
+	err = db.%[11]s(
+		%[12]s%[7]s,
+		id,
+	).Scan(%[8]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateCreate generates the Create method for the struct. As MySQL doesn't
+// support RETURNING, the entry is selected after the insert using either the
+// primary key of the passed in entry or the auto increment ID
+func (g *MySQL) GenerateCreate() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	timestampColumns, timestampValues := g.TimestampValues()
+	columns := append(g.WriteFieldDBNames(""), timestampColumns...)
+	values := append(g.PlaceholderStrings(len(g.ValueFields(g.WriteFields))), timestampValues...)
+
+	insertQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		g.TableDBName(),
+		strings.Join(columns, ", "),
+		strings.Join(values, ", "),
+	)
+	selectQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?",
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.TableDBName(),
+		g.FieldDBName(g.PrimaryFieldOffset()),
+	)
+
+	// Use the primary key of the entry if it's written, otherwise it's
+	// generated by the DB
+	result := "result"
+	lookupID := `	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+`
+	if _, ok := g.WriteFields[g.PrimaryFieldOffset()]; ok {
+		result = "_"
+		lookupID = fmt.Sprintf("\tid := x.%s\n", g.Struct.Field(g.PrimaryFieldOffset()).Name())
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(createTmpl,
+		suffix,
+		g.StructType(),
+		result,
+		strconv.Quote(insertQuery),
+		strings.Join(g.WriteFieldNames("x."), ", "),
+		lookupID,
+		strconv.Quote(selectQuery),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.HelperType(generator.TypeExecQueryRower),
+	)
+
+	return nil
+}
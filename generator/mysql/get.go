@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	getTmpl = `
+// Get%[1]s returns a single entry from DB based on primary key
+func Get%[1]s(%[5]sdb %[8]s, id interface{}) (*%[2]s, error) {
+	var y %[2]s
+	err := db.%[6]s(
+		%[7]s%[3]s,
+		id,
+	).Scan(%[4]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateGet generates the Get method for the struct
+func (g *MySQL) GenerateGet() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	var softDeleteWhere string
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.FieldDBName(g.SoftDeleteFieldOffset))
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(getTmpl,
+		suffix,
+		g.StructType(),
+		strconv.Quote(fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?%s",
+			strings.Join(g.ReadFieldDBNames(""), ", "),
+			g.TableDBName(),
+			g.FieldDBName(g.PrimaryFieldOffset()),
+			softDeleteWhere,
+		)),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.HelperType(generator.TypeQueryRower),
+	)
+
+	return nil
+}
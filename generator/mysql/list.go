@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	listTmpl = `
+// List%[1]ss returns a list of entries from DB based on passed in limit, offset, filters and sorting.
+// The filter should use ? as placeholders
+func List%[1]ss(%[7]sdb %[10]s, limit, offset uint64, filter cruderSQLFilter, sorter cruderSQLSorter) ([]%[2]s, error) {
+	var args []interface{}
+	sqlParts := []string{%[3]s}
+
+	%[4]s
+	if filter != nil {
+		if filters, filterArgs := filter.Where(); filters != "" {
+			sqlParts = append(sqlParts, %[5]s + "(" + filters + ")")
+			args = append(args, filterArgs...)
+		}
+	}
+
+	if sorter != nil {
+		if orderBy := sorter.OrderBy(); orderBy != "" {
+			sqlParts = append(sqlParts, "ORDER BY " + orderBy)
+		}
+	}
+
+	if limit > 0 {
+		sqlParts = append(sqlParts, fmt.Sprintf("LIMIT %%d", limit))
+	}
+	if offset > 0 {
+		if limit == 0 {
+			// MySQL doesn't support OFFSET without LIMIT
+			sqlParts = append(sqlParts, "LIMIT 18446744073709551615")
+		}
+		sqlParts = append(sqlParts, fmt.Sprintf("OFFSET %%d", offset))
+	}
+	rows, err := db.%[8]s(
+		%[9]sstrings.Join(sqlParts, " "),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := []%[2]s{}
+	for rows.Next() {
+		var e %[2]s
+		if err := rows.Scan(%[6]s); err != nil {
+			return nil, err
+		}
+		r = append(r, e)
+	}
+
+	return r, rows.Err()
+}
+`
+)
+
+// GenerateList generates the List method for the struct
+func (g *MySQL) GenerateList() error {
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	var softDeleteWhere, softDeleteWhere2 string
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf("sqlParts = append(sqlParts, %s)", strconv.Quote("WHERE "+g.FieldDBName(g.SoftDeleteFieldOffset)+" IS NULL"))
+		softDeleteWhere2 = "\"AND \""
+	} else {
+		softDeleteWhere2 = "\"WHERE \""
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(listTmpl,
+		suffix,
+		g.StructType(),
+		strconv.Quote(fmt.Sprintf("SELECT %s FROM %s",
+			strings.Join(g.ReadFieldDBNames(""), ", "),
+			g.TableDBName(),
+		)),
+		softDeleteWhere,
+		softDeleteWhere2,
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		g.CtxParam(),
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		g.HelperType(generator.TypeQueryer),
+	)
+
+	return nil
+}
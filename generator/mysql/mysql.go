@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+type (
+	// MySQL generates the CRUD methods for MySQL and MariaDB using database/sql.
+	MySQL struct {
+		*generator.Base
+	}
+
+	// dialect contains the SQL of MySQL needed by generator.Base
+	dialect struct{}
+)
+
+// New returns a MySQL. The fields of the struct can be configured with the
+// options of generator.NewBase, the options of the pg backend are rejected
+func New(pkg *types.Package, t *types.Struct, structModel string) (*MySQL, error) {
+	gen := &MySQL{
+		Base: generator.NewBase(pkg, t, structModel, dialect{}),
+	}
+	if err := gen.RejectTags("type", "omitempty", "version", "conflict"); err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
+
+// Generate generates CRUD code for the passed in functions and writes the
+// formatted file, including the package header, to w
+func (g *MySQL) Generate(w io.Writer, fns ...generator.Function) error {
+	if g.ExternalPkg {
+		if err := g.CheckExportedFields(); err != nil {
+			return err
+		}
+	}
+
+	for _, fn := range fns {
+		var err error
+		switch fn {
+		case generator.Create:
+			err = g.GenerateCreate()
+		case generator.Get:
+			err = g.GenerateGet()
+		case generator.List:
+			err = g.GenerateList()
+		case generator.Update:
+			err = g.GenerateUpdate()
+		case generator.Delete:
+			err = g.GenerateDelete()
+		default:
+			return fmt.Errorf("unknown function %s", fn)
+		}
+		if err != nil {
+			return fmt.Errorf("generating %s: %s", fn, err)
+		}
+	}
+
+	return g.Write(w)
+}
+
+// QuoteIdentifier quotes a MySQL identifier with backticks
+func (dialect) QuoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// Placeholder returns ?, as the arguments are not numbered in MySQL
+func (dialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Now returns NOW()
+func (dialect) Now() string {
+	return "NOW()"
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	updateTmpl = `
+// Update%[1]s updates an entry into DB
+func Update%[1]s(%[8]sdb %[12]s, x %[2]s) (*%[2]s, error) {
+	_, err := db.%[9]s(
+		%[11]s%[3]s,
+		%[4]s,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var y %[2]s
+	err = db.%[10]s(
+		%[11]s%[5]s,
+		x.%[6]s,
+	).Scan(%[7]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateUpdate generates the Update method for the struct. The entry is
+// selected after the update as MySQL doesn't support RETURNING
+func (g *MySQL) GenerateUpdate() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	var setParts []string
+	for _, f := range g.WriteFieldDBNames("") {
+		setParts = append(setParts, f+" = ?")
+	}
+	setParts = append(setParts, g.TimestampSetParts()...)
+
+	var softDeleteWhere string
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.FieldDBName(g.SoftDeleteFieldOffset))
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	primaryFieldName := g.Struct.Field(g.PrimaryFieldOffset()).Name()
+
+	g.Printf(updateTmpl,
+		suffix,
+		g.StructType(),
+		strconv.Quote(fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?%s",
+			g.TableDBName(),
+			strings.Join(setParts, ", "),
+			g.FieldDBName(g.PrimaryFieldOffset()),
+			softDeleteWhere,
+		)),
+		strings.Join(append(g.WriteFieldNames("x."), "x."+primaryFieldName), ", "),
+		strconv.Quote(fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?%s",
+			strings.Join(g.ReadFieldDBNames(""), ", "),
+			g.TableDBName(),
+			g.FieldDBName(g.PrimaryFieldOffset()),
+			softDeleteWhere,
+		)),
+		primaryFieldName,
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.HelperType(generator.TypeExecQueryRower),
+	)
+
+	return nil
+}
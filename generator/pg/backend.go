@@ -0,0 +1,131 @@
+package pg
+
+import (
+	"github.com/pengux/cruder/generator"
+	"github.com/spf13/pflag"
+)
+
+func init() {
+	generator.Register(generator.Backend{
+		Name:  "pg",
+		Short: "Generates CRUD methods for Postgresql, uses the lib/pg package",
+		Flags: func(fs *pflag.FlagSet) {
+			fs.String("table", "", "table name in the database, default to <struct>")
+			fs.StringSlice("conflictfields", []string{}, "Fields used as conflict target in upsert. Default to fields tagged with `cruder:\"conflict\"` or the primary field")
+			fs.Bool("copyin", false, "Also generate a Copy function for createmany, which uses COPY FROM STDIN through lib/pq and must be passed a transaction")
+			fs.Bool("untypedpk", false, "Use interface{} instead of the field types for primary key parameters, as in earlier versions")
+			fs.String("softdeletestrategy", "", "How entries are marked as deleted in the softdelete field: timestamp, bool or status. Default to the strategy matching the type of the field")
+			fs.String("softdeletevalue", defaultSoftDeleteValue, "The value of the softdelete field for deleted entries with the status strategy")
+			fs.Bool("noautotimestamps", false, "Take the values of CreatedAt and UpdatedAt, or the fields tagged with `cruder:\"autocreate\"` and `cruder:\"autoupdate\"`, from the struct instead of setting them to NOW()")
+			fs.String("versionfield", "", "the field to use for optimistic locking in update and delete. Default to the field tagged with `cruder:\"version\"`")
+			fs.String("cursorfield", "", "the field to sort by in listafter, before the primary field. Default to the primary field only")
+			fs.Bool("getmanymap", false, "Return a map keyed by the primary field from getmany instead of a slice")
+			fs.Bool("conflictdonothing", false, "Ignore conflicting entries in upsert (ON CONFLICT DO NOTHING) instead of updating them")
+			fs.Bool("repository", false, "Generate a <struct>Repository with the functions as methods, holding the database handle and the table name, and a matching <struct>Store interface. Only supported by pg, and the Copy method of --copyin needs a repository created with a transaction")
+		},
+		New: newFromOptions,
+	})
+}
+
+// newFromOptions returns a PG configured from the shared options and the
+// flags of the pg backend
+func newFromOptions(opts generator.Options, fs *pflag.FlagSet) (generator.Generator, error) {
+	gen, err := New(opts.Pkg, opts.Struct, opts.StructName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gen.SetOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := fs.GetString("table")
+	if err != nil {
+		return nil, err
+	}
+	if len(table) > 0 {
+		gen.TableName = table
+	}
+
+	conflictFields, err := fs.GetStringSlice("conflictfields")
+	if err != nil {
+		return nil, err
+	}
+	if len(conflictFields) > 0 {
+		err = gen.SetConflictFields(conflictFields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gen.ConflictDoNothing, err = fs.GetBool("conflictdonothing")
+	if err != nil {
+		return nil, err
+	}
+
+	softDeleteStrategy, err := fs.GetString("softdeletestrategy")
+	if err != nil {
+		return nil, err
+	}
+	if len(softDeleteStrategy) > 0 {
+		err = gen.SetSoftDeleteStrategy(softDeleteStrategy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gen.SoftDeleteValue, err = fs.GetString("softdeletevalue")
+	if err != nil {
+		return nil, err
+	}
+
+	versionField, err := fs.GetString("versionfield")
+	if err != nil {
+		return nil, err
+	}
+	if len(versionField) > 0 {
+		err = gen.SetVersionField(versionField)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gen.NoAutoTimestamps, err = fs.GetBool("noautotimestamps")
+	if err != nil {
+		return nil, err
+	}
+
+	gen.CopyIn, err = fs.GetBool("copyin")
+	if err != nil {
+		return nil, err
+	}
+
+	cursorField, err := fs.GetString("cursorfield")
+	if err != nil {
+		return nil, err
+	}
+	if len(cursorField) > 0 {
+		err = gen.SetCursorField(cursorField)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gen.UntypedPrimaryKey, err = fs.GetBool("untypedpk")
+	if err != nil {
+		return nil, err
+	}
+
+	gen.GetManyMap, err = fs.GetBool("getmanymap")
+	if err != nil {
+		return nil, err
+	}
+
+	gen.Repository, err = fs.GetBool("repository")
+	if err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
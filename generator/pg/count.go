@@ -0,0 +1,84 @@
+package pg
+
+import (
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	countTmpl = `
+// Count%[1]s returns the number of entries in DB matching the passed in filter
+func %[8]sCount%[1]s(%[2]s%[7]sfilter cruderSQLFilter) (int64, error) {
+	var args []interface{}
+	sqlParts := []string{` + "`SELECT COUNT(*) FROM %[3]s`" + `}
+%[4]s
+	var n int64
+	err := %[9]s.%[5]s(
+		%[6]sstrings.Join(sqlParts, " "),
+		args...,
+	).Scan(&n)
+
+	return n, err
+}
+`
+
+	existsTmpl = `
+// Exists%[1]s reports whether an entry with the primary key exists in DB
+func %[11]sExists%[1]s(%[2]s%[10]s%[8]s) (bool, error) {
+	var exists bool
+	err := %[12]s.%[3]s(
+		%[4]s` + "`" + `SELECT EXISTS(SELECT 1 FROM %[5]s WHERE %[6]s%[7]s)` + "`" + `,
+		%[9]s,
+	).Scan(&exists)
+
+	return exists, err
+}
+`
+)
+
+// GenerateCount generates the Count method for the struct, which uses the same
+// conditions as List
+func (g *PG) GenerateCount() error {
+	g.AddImport("strings")
+
+	g.Printf(countTmpl,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.tableName(),
+		g.whereCode(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// GenerateExists generates the Exists method for the struct
+func (g *PG) GenerateExists() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	params, args := g.primaryParams()
+
+	g.Printf(existsTmpl,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.tableName(),
+		g.primaryWhere(1),
+		g.softDeleteWhere(),
+		params,
+		strings.Join(args, ", "),
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
@@ -1,40 +1,165 @@
 package pg
 
-import "strings"
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
 
 const (
 	createTmpl = `
 // Create%[1]s inserts an entry into DB
-func Create%[1]s(db cruderQueryRower, x %[2]s) (*%[2]s, error) {
-	var y %[2]s
-	err := db.QueryRow(
-		` + "`" + `INSERT INTO %s (%s) VALUES (%s)
-		RETURNING %s` + "`" + `,
-		%s,
-	).Scan(%s)
+func %[14]sCreate%[1]s(%[3]s%[13]sx %[2]s) (*%[2]s, error) {
+%[12]s	var y %[2]s
+	err := %[15]s.%[4]s(
+		%[5]s` + "`" + `INSERT INTO %[6]s (%[7]s) VALUES (%[8]s)
+		RETURNING %[9]s` + "`" + `,
+		%[10]s,
+	).Scan(%[11]s)
 
 	return &y, err
 }
+`
+
+	// insertValueTmpl adds a value to the VALUES of an INSERT statement
+	insertValueTmpl = `	args = append(args, %[1]s)
+	values = append(values, fmt.Sprintf("$%%d", len(args)))
+`
+
+	// insertOmitEmptyValueTmpl adds a value to the VALUES of an INSERT
+	// statement, or DEFAULT if the field has its zero value
+	insertOmitEmptyValueTmpl = `	if %[2]s {
+		values = append(values, "DEFAULT")
+	} else {
+		args = append(args, %[1]s)
+		values = append(values, fmt.Sprintf("$%%d", len(args)))
+	}
 `
 )
 
 // GenerateCreate generates the Create method for the struct
-func (g *PG) GenerateCreate() {
-	g.GenerateType(typeQueryRowerInterface)
-
-	var suffix string
-	if !g.SkipSuffix {
-		suffix = g.structModel
+func (g *PG) GenerateCreate() error {
+	createFields := g.createFields()
+	values, args, code, err := g.insertValues(createFields)
+	if err != nil {
+		return err
 	}
 
+	timestampColumns, _ := g.TimestampValues()
+	columns := append(g.FieldDBNames(createFields, ""), timestampColumns...)
+
 	g.Printf(createTmpl,
-		suffix,
-		g.structModel,
-		g.TableName,
-		strings.Join(g.writeFieldDBNames(""), ", "),
-		strings.Join(g.placeholderStrings(len(g.writeFieldDBNames(""))), ", "),
-		strings.Join(g.readFieldDBNames(""), ", "),
-		strings.Join(g.writeFieldNames("x."), ", "),
-		strings.Join(g.readFieldNames("&y."), ", "),
+		g.funcSuffix(),
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.tableName(),
+		strings.Join(columns, ", "),
+		values,
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		args,
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		code,
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
 	)
+
+	return nil
+}
+
+// createFields returns the fields inserted by Create and CreateMany, which are
+// the write fields and the primary fields if the key is composite or tagged
+// with `cruder:"pk"`, as such keys are not generated by the database. The
+// primary fields are still left out of the write fields set by updates
+func (g *PG) createFields() map[int]string {
+	fields := make(map[int]string, len(g.WriteFields)+len(g.PrimaryFields))
+	for k, f := range g.WriteFields {
+		fields[k] = f
+	}
+
+	explicit := len(g.PrimaryFields) > 1
+	for k := range g.PrimaryFields {
+		if g.FieldHasTag(k, "pk") {
+			explicit = true
+		}
+	}
+	if explicit {
+		for k, f := range g.PrimaryFields {
+			fields[k] = f
+		}
+	}
+
+	return g.ValueFields(fields)
+}
+
+// insertValues returns the VALUES and the arguments of an INSERT statement for
+// the fields followed by the timestamp fields. If any of the fields is tagged
+// with `cruder:"omitempty"`, the code building the values and the arguments at
+// runtime is returned too, as DEFAULT is inserted instead of zero values
+func (g *PG) insertValues(fields map[int]string) (string, string, string, error) {
+	fieldArgs := g.FieldArgs(fields, "x.")
+	_, timestampValues := g.TimestampValues()
+
+	var omitEmpty bool
+	for k := range fields {
+		if _, ok := g.omitEmptyFields[k]; ok {
+			omitEmpty = true
+		}
+	}
+	if !omitEmpty {
+		values := append(g.PlaceholderStrings(len(fieldArgs)), timestampValues...)
+		return strings.Join(values, ", "), strings.Join(fieldArgs, ", "), "", nil
+	}
+
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	code := "\tvar args []interface{}\n\tvar values []string\n"
+	for i, k := range generator.SortedOffsets(fields) {
+		if _, ok := g.omitEmptyFields[k]; !ok {
+			code += fmt.Sprintf(insertValueTmpl, fieldArgs[i])
+			continue
+		}
+
+		isZero, err := g.zeroCheck(k, "x."+fields[k])
+		if err != nil {
+			return "", "", "", err
+		}
+		code += fmt.Sprintf(insertOmitEmptyValueTmpl, fieldArgs[i], isZero)
+	}
+	for _, v := range timestampValues {
+		code += fmt.Sprintf("\tvalues = append(values, %q)\n", v)
+	}
+	code += "\n"
+
+	return "` + strings.Join(values, \", \") + `", "args...", code, nil
+}
+
+// zeroCheck returns the expression reporting whether expr, which is of the
+// type of the field, has its zero value
+func (g *PG) zeroCheck(k int, expr string) (string, error) {
+	t := g.Struct.Field(k).Type()
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "!" + expr, nil
+		case u.Info()&types.IsString != 0:
+			return expr + ` == ""`, nil
+		case u.Info()&types.IsNumeric != 0:
+			return expr + " == 0", nil
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return expr + " == nil", nil
+	case *types.Struct, *types.Array:
+		if types.Comparable(t) {
+			return fmt.Sprintf("%s == (%s{})", expr, g.TypeName(t)), nil
+		}
+	}
+
+	return "", fmt.Errorf("the zero value of the field %s can't be checked for omitempty", g.Struct.Field(k).Name())
 }
@@ -0,0 +1,188 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+// maxQueryParams is the maximum number of parameters in a Postgresql query
+const maxQueryParams = 65535
+
+const (
+	createManyTmpl = `
+// CreateMany%[1]s inserts multiple entries into DB using multi-row inserts. The entries
+// are inserted in chunks of %[3]d to stay below the limit of query parameters, use
+// a transaction as db if all chunks should be inserted atomically
+func %[16]sCreateMany%[1]s(%[4]s%[15]sxs []%[2]s) ([]%[2]s, error) {
+	r := make([]%[2]s, 0, len(xs))
+	for start := 0; start < len(xs); start += %[3]d {
+		chunk := xs[start:]
+		if len(chunk) > %[3]d {
+			chunk = chunk[:%[3]d]
+		}
+
+		values := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*%[5]d)
+		for i := range chunk {
+			x := &chunk[i]
+			p := i * %[5]d
+			values[i] = fmt.Sprintf("(%[6]s)", %[7]s)
+			args = append(args, %[8]s)
+		}
+
+		rows, err := %[17]s.%[9]s(
+			%[10]s` + "`" + `INSERT INTO %[11]s (%[12]s) VALUES ` + "`" + ` + strings.Join(values, ", ") + ` + "`" + `
+			RETURNING %[13]s` + "`" + `,
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var e %[2]s
+			if err := rows.Scan(%[14]s); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			r = append(r, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+`
+
+	copyTmpl = `
+// Copy%[1]s inserts multiple entries into DB using COPY FROM STDIN, which is faster than
+// CreateMany%[1]s for large imports but doesn't return the entries. lib/pq requires db to
+// be a transaction
+func %[12]sCopy%[1]s(%[3]s%[11]sxs []%[2]s) error {
+	stmt, err := %[13]s.%[4]s(%[5]s%[6]s)
+	if err != nil {
+		return err
+	}
+
+	%[10]sfor i := range xs {
+		x := &xs[i]
+		if _, err := stmt.%[7]s(%[5]s%[8]s); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	// Flush the buffered data
+	if _, err := stmt.%[7]s(%[9]s); err != nil {
+		stmt.Close()
+		return err
+	}
+
+	return stmt.Close()
+}
+`
+)
+
+// GenerateCreateMany generates the CreateMany method for the struct, and the Copy
+// method if CopyIn is set
+func (g *PG) GenerateCreateMany() error {
+	createFields := g.createFields()
+	n := len(createFields)
+	if n == 0 {
+		return errors.New("no write fields to insert")
+	}
+
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	rowPlaceholders := make([]string, n)
+	rowArgs := make([]string, n)
+	for i := range rowPlaceholders {
+		rowPlaceholders[i] = "$%d"
+		rowArgs[i] = "p+" + strconv.Itoa(i+1)
+	}
+
+	columns := g.FieldDBNames(createFields, "")
+	timestampColumns, timestampValues := g.TimestampValues()
+	columns = append(columns, timestampColumns...)
+	rowPlaceholders = append(rowPlaceholders, timestampValues...)
+
+	g.Printf(createManyTmpl,
+		g.funcSuffix(),
+		g.StructType(),
+		maxQueryParams/n,
+		g.CtxParam(),
+		n,
+		strings.Join(rowPlaceholders, ", "),
+		strings.Join(rowArgs, ", "),
+		strings.Join(g.FieldArgs(createFields, "x."), ", "),
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		g.tableName(),
+		strings.Join(columns, ", "),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		g.dbParam(generator.TypeQueryer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	if !g.CopyIn {
+		return nil
+	}
+
+	g.AddImport("github.com/lib/pq")
+
+	// The table of a repository is only known at runtime, so it can't be
+	// split into schema and table
+	copyIn := "pq.CopyIn(" + g.tableExpr()
+	if parts := strings.SplitN(g.TableName, ".", 2); len(parts) == 2 && !g.Repository {
+		copyIn = fmt.Sprintf("pq.CopyInSchema(%s, %s", strconv.Quote(parts[0]), strconv.Quote(parts[1]))
+	}
+	for _, f := range columns {
+		copyIn += ", " + strconv.Quote(f)
+	}
+	copyIn += ")"
+
+	// COPY can't use NOW(), so the timestamp fields are set to the same time
+	// on the client
+	copyArgs := g.FieldArgs(createFields, "x.")
+	var now string
+	if len(timestampColumns) > 0 {
+		g.AddImport("time")
+		now = "now := time.Now()\n"
+		for range timestampColumns {
+			copyArgs = append(copyArgs, "now")
+		}
+	}
+
+	var ctx string
+	if !g.NoContext {
+		ctx = "ctx"
+	}
+
+	g.Printf(copyTmpl,
+		g.funcSuffix(),
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("Prepare"),
+		strings.TrimSpace(g.CtxArg()),
+		copyIn,
+		g.DBMethod("Exec"),
+		strings.Join(copyArgs, ", "),
+		ctx,
+		now,
+		g.dbParam(generator.TypePreparer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
@@ -1,14 +1,19 @@
 package pg
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
 
 const (
 	deleteTmpl = `
 // Delete%[1]s deletes an entry from DB
-func Delete%[1]s(db cruderExecer, id interface{}) error {
-	result, err := db.Exec(
-		` + "`" + `%s` + "`" + `,
-		id,
+func %[10]sDelete%[1]s(%[2]s%[9]s%[6]s) error {
+	result, err := %[11]s.%[3]s(
+		%[4]s` + "`" + `%[5]s` + "`" + `,
+		%[7]s,
 	)
 	if err != nil {
 		return err
@@ -18,7 +23,7 @@ func Delete%[1]s(db cruderExecer, id interface{}) error {
 		if err != nil {
 			return err
 		}
-		return errors.New("sql: no rows affected")
+		return %[8]s
 	}
 
 	return nil
@@ -27,32 +32,62 @@ func Delete%[1]s(db cruderExecer, id interface{}) error {
 )
 
 // GenerateDelete generates the Delete method for the struct
-func (g *PG) GenerateDelete() {
-	g.GenerateType(typeExecerInterface)
-	g.addImport("errors")
+func (g *PG) GenerateDelete() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	g.AddImport("errors")
+
+	params, args := g.primaryParams()
+
+	// The version of the entry must match the passed in version, and is
+	// incremented on soft deletion
+	where := g.primaryWhere(1)
+	noRowsErr := `errors.New("sql: no rows affected")`
+	var versionSet string
+	if g.versionFieldOffset != -1 {
+		versionField := g.Struct.Field(g.versionFieldOffset)
+		where += g.versionWhere(len(args) + 1)
+		versionSet = ", " + g.versionSet("")
+		params += ", " + generator.ParamName(versionField.Name()) + " " + g.TypeName(versionField.Type())
+		args = append(args, generator.ParamName(versionField.Name()))
+		noRowsErr = g.generateStaleError()
+	}
 
 	var deleteQuery string
-	if g.softDeleteFieldOffset != -1 {
-		deleteQuery = fmt.Sprintf("UPDATE %s SET %s = NOW() WHERE %s = $1 AND %s IS NULL",
-			g.TableName,
-			g.fieldDBName(g.softDeleteFieldOffset),
-			g.fieldDBName(g.primaryFieldOffset),
-			g.fieldDBName(g.softDeleteFieldOffset),
+	if g.SoftDeleteFieldOffset != -1 {
+		set, err := g.softDeleteSet(true)
+		if err != nil {
+			return err
+		}
+		deleteQuery = fmt.Sprintf("UPDATE %s SET %s%s WHERE %s%s",
+			g.tableName(),
+			set,
+			versionSet,
+			where,
+			g.softDeleteWhere(),
 		)
 	} else {
-		deleteQuery = fmt.Sprintf("DELETE FROM %s WHERE %s = $1",
-			g.TableName,
-			g.fieldDBName(g.primaryFieldOffset),
+		deleteQuery = fmt.Sprintf("DELETE FROM %s WHERE %s",
+			g.tableName(),
+			where,
 		)
 	}
 
-	var suffix string
-	if !g.SkipSuffix {
-		suffix = g.structModel
-	}
-
 	g.Printf(deleteTmpl,
-		suffix,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.CtxArg(),
 		deleteQuery,
+		params,
+		strings.Join(args, ", "),
+		noRowsErr,
+		g.dbParam(generator.TypeExecer),
+		g.receiver(),
+		g.dbExpr(),
 	)
+
+	return nil
 }
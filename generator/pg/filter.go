@@ -0,0 +1,204 @@
+package pg
+
+import (
+	"go/types"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	filterTmpl = `
+// %[1]sFilter is a composable filter of %[1]s entries, which renders to SQL with numbered
+// placeholders. It can be passed as filter to the List and Count functions. The zero value
+// matches all entries and is ignored when combined with other filters
+type %[1]sFilter struct {
+	op       string // AND, OR or NOT for combined filters, empty for predicates
+	expr     string // SQL of a predicate with ? as placeholders
+	args     []interface{}
+	children []%[1]sFilter
+}
+
+// Where returns the SQL condition and arguments of the filter
+func (f %[1]sFilter) Where() (string, []interface{}) {
+	var args []interface{}
+	return f.render(&args), args
+}
+
+// And returns a filter matching the entries that match f and all the passed in filters
+func (f %[1]sFilter) And(filters ...%[1]sFilter) %[1]sFilter {
+	return %[1]sFilter{op: "AND", children: append([]%[1]sFilter{f}, filters...)}
+}
+
+// Or returns a filter matching the entries that match f or any of the passed in filters
+func (f %[1]sFilter) Or(filters ...%[1]sFilter) %[1]sFilter {
+	return %[1]sFilter{op: "OR", children: append([]%[1]sFilter{f}, filters...)}
+}
+
+// Not returns a filter matching the entries that don't match f
+func (f %[1]sFilter) Not() %[1]sFilter {
+	return %[1]sFilter{op: "NOT", children: []%[1]sFilter{f}}
+}
+
+func (f %[1]sFilter) isZero() bool {
+	return f.op == "" && f.expr == ""
+}
+
+// and returns a filter matching the entries that match f and the predicate
+func (f %[1]sFilter) and(expr string, args ...interface{}) %[1]sFilter {
+	p := %[1]sFilter{expr: expr, args: args}
+	if f.isZero() {
+		return p
+	}
+
+	return f.And(p)
+}
+
+// in returns a filter matching the entries where column has one of the values
+func (f %[1]sFilter) in(column string, values []interface{}) %[1]sFilter {
+	if len(values) == 0 {
+		return f.and("FALSE")
+	}
+
+	return f.and(column+" IN (?"+strings.Repeat(", ?", len(values)-1)+")", values...)
+}
+
+// render returns the SQL of the filter and appends its arguments to args, the
+// placeholders are numbered after the arguments already in args
+func (f %[1]sFilter) render(args *[]interface{}) string {
+	switch f.op {
+	case "":
+		var b strings.Builder
+		n := 0
+		for _, r := range f.expr {
+			if r != '?' {
+				b.WriteRune(r)
+				continue
+			}
+
+			*args = append(*args, f.args[n])
+			n++
+			fmt.Fprintf(&b, "$%%d", len(*args))
+		}
+
+		return b.String()
+	case "NOT":
+		s := f.children[0].render(args)
+		if s == "" {
+			return ""
+		}
+
+		return "NOT (" + s + ")"
+	default:
+		var parts []string
+		for _, c := range f.children {
+			if s := c.render(args); s != "" {
+				parts = append(parts, "("+s+")")
+			}
+		}
+
+		return strings.Join(parts, " "+f.op+" ")
+	}
+}
+`
+
+	// filterPredicateTmpl is a predicate comparing a column to a single value
+	filterPredicateTmpl = `
+// %[2]s%[3]s filters on %[4]s %[5]s the value
+func (f %[1]sFilter) %[2]s%[3]s(v %[6]s) %[1]sFilter {
+	return f.and("%[4]s %[5]s ?", %[7]s)
+}
+`
+
+	filterInTmpl = `
+// %[2]sIn filters on %[3]s being one of the values
+func (f %[1]sFilter) %[2]sIn(values ...%[4]s) %[1]sFilter {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = %[5]s
+	}
+
+	return f.in("%[3]s", args)
+}
+`
+
+	filterBetweenTmpl = `
+// %[2]sBetween filters on %[3]s being between from and to, inclusive
+func (f %[1]sFilter) %[2]sBetween(from, to %[4]s) %[1]sFilter {
+	return f.and("%[3]s BETWEEN ? AND ?", from, to)
+}
+`
+
+	filterNullTmpl = `
+// %[2]sIsNull filters on %[3]s being NULL
+func (f %[1]sFilter) %[2]sIsNull() %[1]sFilter {
+	return f.and("%[3]s IS NULL")
+}
+
+// %[2]sIsNotNull filters on %[3]s not being NULL
+func (f %[1]sFilter) %[2]sIsNotNull() %[1]sFilter {
+	return f.and("%[3]s IS NOT NULL")
+}
+`
+)
+
+// GenerateFilter generates a filter builder for the struct with typed predicates
+// for every read field, which can be used as filter in List and Count
+func (g *PG) GenerateFilter() error {
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	g.Printf(filterTmpl, g.StructName)
+
+	for _, k := range generator.SortedOffsets(g.ReadFields) {
+		name := g.ReadFields[k]
+		column := g.FieldDBName(k)
+		t := g.Struct.Field(k).Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		typeName := g.TypeName(t)
+
+		// The values of fields stored as JSON are passed as cruderJSONB.
+		// Postgresql has no equality operator for json, so only the NULL
+		// checks are generated for type=json
+		arg := "v"
+		if _, ok := g.jsonFields[k]; ok {
+			if g.FieldTagValue(k, "type") != "jsonb" {
+				g.Printf(filterNullTmpl, g.StructName, name, column)
+				continue
+			}
+			arg = "cruderJSONB{v}"
+		}
+
+		ops := []struct{ name, op string }{{"Eq", "="}, {"Ne", "<>"}}
+		ordered := isOrdered(t)
+		if ordered {
+			ops = append(ops, []struct{ name, op string }{{"Lt", "<"}, {"Lte", "<="}, {"Gt", ">"}, {"Gte", ">="}}...)
+		}
+		if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsString != 0 {
+			ops = append(ops, []struct{ name, op string }{{"Like", "LIKE"}, {"ILike", "ILIKE"}}...)
+		}
+
+		for _, o := range ops {
+			g.Printf(filterPredicateTmpl, g.StructName, name, o.name, column, o.op, typeName, arg)
+		}
+		g.Printf(filterInTmpl, g.StructName, name, column, typeName, arg)
+		if ordered {
+			g.Printf(filterBetweenTmpl, g.StructName, name, column, typeName)
+		}
+		g.Printf(filterNullTmpl, g.StructName, name, column)
+	}
+
+	return nil
+}
+
+// isOrdered reports whether values of the type can be compared with < and >
+// in SQL, i.e. it's a number, string or time.Time
+func isOrdered(t types.Type) bool {
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time" {
+		return true
+	}
+
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&(types.IsNumeric|types.IsString) != 0
+}
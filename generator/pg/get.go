@@ -1,19 +1,20 @@
 package pg
 
 import (
-	"fmt"
 	"strings"
+
+	"github.com/pengux/cruder/generator"
 )
 
 const (
 	getTmpl = `
 // Get%[1]s returns a single entry from DB based on primary key
-func Get%[1]s(db cruderQueryRower, id interface{}) (*%[2]s, error) {
+func %[14]sGet%[1]s(%[3]s%[13]s%[11]s) (*%[2]s, error) {
 	var y %[2]s
-	err := db.QueryRow(
-		` + "`" + `SELECT %s FROM %s WHERE %s = $1%s` + "`" + `,
-		id,
-	).Scan(%s)
+	err := %[15]s.%[4]s(
+		%[5]s` + "`" + `SELECT %[6]s FROM %[7]s WHERE %[8]s%[9]s` + "`" + `,
+		%[12]s,
+	).Scan(%[10]s)
 
 	return &y, err
 }
@@ -21,26 +22,30 @@ func Get%[1]s(db cruderQueryRower, id interface{}) (*%[2]s, error) {
 )
 
 // GenerateGet generates the Get method for the struct
-func (g *PG) GenerateGet() {
-	g.GenerateType(typeQueryRowerInterface)
-
-	var softDeleteWhere string
-	if g.softDeleteFieldOffset != -1 {
-		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.fieldDBName(g.softDeleteFieldOffset))
+func (g *PG) GenerateGet() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
 	}
 
-	var suffix string
-	if !g.SkipSuffix {
-		suffix = g.structModel
-	}
+	params, args := g.primaryParams()
 
 	g.Printf(getTmpl,
-		suffix,
-		g.structModel,
-		strings.Join(g.readFieldDBNames(""), ", "),
-		g.TableName,
-		g.fieldDBName(g.primaryFieldOffset),
-		softDeleteWhere,
-		strings.Join(g.readFieldNames("&y."), ", "),
+		g.funcSuffix(),
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.tableName(),
+		g.primaryWhere(1),
+		g.softDeleteWhere(),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		params,
+		strings.Join(args, ", "),
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
 	)
+
+	return nil
 }
@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	getManyTmpl = `
+// GetMany%[1]s returns the entries from DB with the passed in primary keys, in a single query.
+// Primary keys without an entry are left out of the result
+func %[16]sGetMany%[1]s(%[3]s%[15]sids []%[4]s) (%[5]s, error) {
+	rows, err := %[17]s.%[6]s(
+		%[7]s` + "`" + `SELECT %[8]s FROM %[9]s WHERE %[10]s = ANY($1)%[11]s` + "`" + `,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r := make(%[5]s, %[12]s)
+	for rows.Next() {
+		var e %[2]s
+		if err := rows.Scan(%[13]s); err != nil {
+			return nil, err
+		}
+		%[14]s
+	}
+
+	return r, rows.Err()
+}
+`
+)
+
+// GenerateGetMany generates the GetMany method for the struct, which returns
+// either a slice or a map keyed by the primary field if GetManyMap is set
+func (g *PG) GenerateGetMany() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	g.AddImport("github.com/lib/pq")
+
+	if len(g.PrimaryFields) > 1 {
+		return fmt.Errorf("composite primary keys are not supported")
+	}
+
+	var primaryFieldOffset int
+	for k := range g.PrimaryFields {
+		primaryFieldOffset = k
+	}
+	primaryField := g.Struct.Field(primaryFieldOffset)
+	idType := g.TypeName(primaryField.Type())
+
+	result := "[]" + g.StructType()
+	size := "0, len(ids)"
+	add := "r = append(r, e)"
+	if g.GetManyMap {
+		if !types.Comparable(primaryField.Type()) {
+			return fmt.Errorf("the primary field %s can't be used as map key", primaryField.Name())
+		}
+		if _, ok := g.ReadFields[primaryFieldOffset]; !ok {
+			return fmt.Errorf("the primary field %s must be a read field to be used as map key", primaryField.Name())
+		}
+
+		result = fmt.Sprintf("map[%s]%s", idType, g.StructType())
+		size = "len(ids)"
+		add = fmt.Sprintf("r[e.%s] = e", primaryField.Name())
+	}
+
+	g.Printf(getManyTmpl,
+		g.funcSuffix(),
+		g.StructType(),
+		g.CtxParam(),
+		idType,
+		result,
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.tableName(),
+		g.FieldDBName(primaryFieldOffset),
+		g.softDeleteWhere(),
+		size,
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		add,
+		g.dbParam(generator.TypeQueryer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
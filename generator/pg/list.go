@@ -2,24 +2,19 @@ package pg
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"github.com/pengux/cruder/generator"
 )
 
 const (
 	listTmpl = `
-// List%[1]ss returns a list of entries from DB based on passed in limit, offset, filters and sorting
-func List%[1]ss(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter, sorter cruderSQLSorter) ([]%[2]s, error) {
+// %[10]s%[1]s returns a list of %[11]sentries from DB based on passed in limit, offset, filters and sorting
+func %[13]s%[10]s%[1]s(%[7]s%[12]slimit, offset uint64, filter cruderSQLFilter, sorter cruderSQLSorter) ([]%[2]s, error) {
 	var args []interface{}
 	sqlParts := []string{` + "`SELECT %[3]s FROM %[4]s`" + `}
-
-	%[5]s
-	if filter != nil {
-		if filters, filterArgs := filter.Where(); filters != "" {
-			sqlParts = append(sqlParts, %[6]s + filters)
-			args = append(args, filterArgs...)
-		}
-	}
-
+%[5]s
 	if sorter != nil {
 		if orderBy := sorter.OrderBy(); orderBy != "" {
 			sqlParts = append(sqlParts, "ORDER BY " + orderBy)
@@ -32,8 +27,8 @@ func List%[1]ss(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter,
 	if offset > 0 {
 		sqlParts = append(sqlParts, fmt.Sprintf("OFFSET %%d", offset))
 	}
-	rows, err := db.Query(
-		strings.Join(sqlParts, " "),
+	rows, err := %[14]s.%[8]s(
+		%[9]sstrings.Join(sqlParts, " "),
 		args...,
 	)
 	if err != nil {
@@ -44,7 +39,7 @@ func List%[1]ss(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter,
 	r := []%[2]s{}
 	for rows.Next() {
         var e %[2]s
-        if err := rows.Scan(%[7]s); err != nil {
+        if err := rows.Scan(%[6]s); err != nil {
             return nil, err
         }
         r = append(r, e)
@@ -52,37 +47,74 @@ func List%[1]ss(db cruderQueryer, limit, offset uint64, filter cruderSQLFilter,
 
 	return r, rows.Err()
 }
+`
+
+	// whereTmpl adds the soft delete and filter conditions to sqlParts and
+	// the filter arguments to args. The filter is parenthesized as it can
+	// contain OR, which binds looser than the AND of the following conditions
+	whereTmpl = `
+	%[1]s
+	if filter != nil {
+		if filters, filterArgs := filter.Where(); filters != "" {
+			sqlParts = append(sqlParts, %[2]s + "(" + filters + ")")
+			args = append(args, filterArgs...)
+		}
+	}
 `
 )
 
-// GenerateList generates the Get method for the struct
-func (g *PG) GenerateList() {
-	g.GenerateType(typeQueryerInterface)
-	g.GenerateType(typeSQLFilterInterface)
-	g.GenerateType(typeSQLSorterInterface)
-	g.addImport("fmt")
-	g.addImport("strings")
+// GenerateList generates the List method for the struct
+func (g *PG) GenerateList() error {
+	g.AddImport("fmt")
+	g.AddImport("strings")
 
+	g.Printf(listTmpl,
+		g.listSuffix(),
+		g.StructType(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.tableName(),
+		g.whereCode(),
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		g.CtxParam(),
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		"List",
+		"",
+		g.dbParam(generator.TypeQueryer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// listSuffix returns the suffix of the list functions, which are plural, e.g.
+// ListFoos, unlike the methods of a repository
+func (g *PG) listSuffix() string {
+	if g.Repository {
+		return ""
+	}
+
+	return g.funcSuffix() + "s"
+}
+
+// whereCode returns the code building the WHERE clause from the soft delete
+// field and the filter, shared by the functions taking a cruderSQLFilter
+func (g *PG) whereCode() string {
+	return g.softDeleteWhereCode(false)
+}
+
+// softDeleteWhereCode returns the code building the WHERE clause from the
+// filter and the soft delete field, matching either deleted or not deleted
+// entries
+func (g *PG) softDeleteWhereCode(deleted bool) string {
 	var softDeleteWhere, softDeleteWhere2 string
-	if g.softDeleteFieldOffset != -1 {
-		softDeleteWhere = fmt.Sprintf("sqlParts = append(sqlParts, \"WHERE %s IS NULL\")", g.fieldDBName(g.softDeleteFieldOffset))
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf("sqlParts = append(sqlParts, %s)", strconv.Quote("WHERE "+g.softDeleteCond("", deleted)))
 		softDeleteWhere2 = "\" AND \""
 	} else {
 		softDeleteWhere2 = "\"WHERE \""
 	}
 
-	var suffix string
-	if !g.SkipSuffix {
-		suffix = g.structModel
-	}
-
-	g.Printf(listTmpl,
-		suffix,
-		g.structModel,
-		strings.Join(g.readFieldDBNames(""), ", "),
-		g.TableName,
-		softDeleteWhere,
-		softDeleteWhere2,
-		strings.Join(g.readFieldNames("&e."), ", "),
-	)
+	return fmt.Sprintf(whereTmpl, softDeleteWhere, softDeleteWhere2)
 }
@@ -0,0 +1,150 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	listAfterTmpl = `
+// %[3]s is the decoded form of the cursors used by List%[1]sAfter
+type %[3]s struct {
+%[4]s}
+
+// List%[1]sAfter returns a page of at most limit entries from DB ordered by (%[5]s), starting
+// after the passed in cursor. An empty cursor returns the first page. The returned cursor points
+// to the last entry of the page and is empty if the page is not full, i.e. there are no more entries
+func %[18]sList%[1]sAfter(%[6]s%[17]scursor string, limit uint64, filter cruderSQLFilter) ([]%[2]s, string, error) {
+	var args []interface{}
+	sqlParts := []string{` + "`SELECT %[7]s FROM %[8]s`" + `}
+%[9]s
+	if cursor != "" {
+		var c %[3]s
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err == nil {
+			err = json.Unmarshal(b, &c)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor: %%s", err)
+		}
+
+		args = append(args, %[10]s)
+		keyset := fmt.Sprintf("(%[5]s) > (%[11]s)", %[12]s)
+		if len(sqlParts) > 1 {
+			keyset = "AND " + keyset
+		} else {
+			keyset = "WHERE " + keyset
+		}
+		sqlParts = append(sqlParts, keyset)
+	}
+
+	sqlParts = append(sqlParts, "ORDER BY %[5]s")
+	if limit > 0 {
+		sqlParts = append(sqlParts, fmt.Sprintf("LIMIT %%d", limit))
+	}
+	rows, err := %[19]s.%[13]s(
+		%[14]sstrings.Join(sqlParts, " "),
+		args...,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	r := []%[2]s{}
+	for rows.Next() {
+		var e %[2]s
+		if err := rows.Scan(%[15]s); err != nil {
+			return nil, "", err
+		}
+		r = append(r, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if limit > 0 && uint64(len(r)) == limit {
+		e := r[len(r)-1]
+		b, err := json.Marshal(%[3]s{%[16]s})
+		if err != nil {
+			return nil, "", err
+		}
+		next = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return r, next, nil
+}
+`
+)
+
+// GenerateListAfter generates the ListAfter method for the struct, which paginates
+// using a cursor on the cursor field (if set) and the primary fields
+func (g *PG) GenerateListAfter() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	var keys []int
+	if _, ok := g.PrimaryFields[g.cursorFieldOffset]; g.cursorFieldOffset != -1 && !ok {
+		keys = append(keys, g.cursorFieldOffset)
+	}
+	keys = append(keys, generator.SortedOffsets(g.PrimaryFields)...)
+
+	var (
+		cursorFields, keyColumns, placeholders, placeholderArgs, cursorArgs, cursorValues []string
+	)
+	for i, k := range keys {
+		f := g.Struct.Field(k)
+		if _, ok := g.ReadFields[k]; !ok {
+			return fmt.Errorf("the field %s must be a read field to be used in a cursor", f.Name())
+		}
+
+		cursorFields = append(cursorFields, fmt.Sprintf("\t%s %s `json:\"%d\"`\n", f.Name(), g.TypeName(f.Type()), i))
+		keyColumns = append(keyColumns, g.FieldDBName(k))
+		placeholders = append(placeholders, "$%d")
+		placeholderArgs = append(placeholderArgs, fmt.Sprintf("len(args)-%d", len(keys)-1-i))
+		cursorArgs = append(cursorArgs, "c."+f.Name())
+		cursorValues = append(cursorValues, "e."+f.Name())
+	}
+	placeholderArgs[len(placeholderArgs)-1] = "len(args)"
+
+	g.AddImport("encoding/base64")
+	g.AddImport("encoding/json")
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	g.Printf(listAfterTmpl,
+		g.funcSuffix(),
+		g.StructType(),
+		lowerFirst(g.StructName)+"Cursor",
+		strings.Join(cursorFields, ""),
+		strings.Join(keyColumns, ", "),
+		g.CtxParam(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.tableName(),
+		g.whereCode(),
+		strings.Join(cursorArgs, ", "),
+		strings.Join(placeholders, ", "),
+		strings.Join(placeholderArgs, ", "),
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		strings.Join(cursorValues, ", "),
+		g.dbParam(generator.TypeQueryer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// lowerFirst returns s with the first letter in lower case
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	patchTmpl = `
+// %[2]sPatch contains the fields to update in Patch%[1]s, nil fields are left unchanged
+type %[2]sPatch struct {
+%[3]s}
+
+// Patch%[1]s updates the fields of an entry in DB which are set in the patch and
+// returns the updated entry
+func %[17]sPatch%[1]s(%[4]s%[15]s%[5]s, patch %[2]sPatch) (*%[16]s, error) {
+	var args []interface{}
+	var setParts []string
+%[6]s%[7]s
+	var where []string
+%[8]s
+	var y %[16]s
+	err := %[18]s.%[9]s(
+		%[10]s"UPDATE "+%[11]s+" SET "+strings.Join(setParts, ", ")+" WHERE "+strings.Join(where, " AND ")+%[12]s,
+		args...,
+	).Scan(%[13]s)
+%[14]s
+	return &y, err
+}
+`
+
+	patchSetTmpl = `	if patch.%[1]s != nil {
+		args = append(args, %[3]s)
+		setParts = append(setParts, fmt.Sprintf("%[2]s = $%%d", len(args)))
+	}
+`
+
+	patchWhereTmpl = `	args = append(args, %[1]s)
+	where = append(where, fmt.Sprintf("%[2]s = $%%d", len(args)))
+`
+)
+
+// GeneratePatch generates the Patch method for the struct and the struct with
+// the optional fields to update
+func (g *PG) GeneratePatch() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	// The version field is incremented instead of being set from the patch
+	writeFields := g.ValueFields(g.WriteFields)
+	delete(writeFields, g.versionFieldOffset)
+
+	// The fields set by the database are added to every update
+	staticSetParts := g.TimestampSetParts()
+	if g.versionFieldOffset != -1 {
+		staticSetParts = append(staticSetParts, g.versionSet(""))
+	}
+	if len(writeFields) == 0 && len(staticSetParts) == 0 {
+		return errors.New("no write fields to update")
+	}
+
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	var fields, sets string
+	for _, k := range generator.SortedOffsets(writeFields) {
+		f := g.Struct.Field(k)
+		arg := "*patch." + f.Name()
+		if _, ok := g.jsonFields[k]; ok {
+			arg = g.jsonArg(k, "patch."+f.Name())
+		}
+		fields += fmt.Sprintf("\t%s *%s\n", f.Name(), g.TypeName(f.Type()))
+		sets += fmt.Sprintf(patchSetTmpl, f.Name(), g.FieldDBName(k), arg)
+	}
+
+	// Without fields set by the database, an empty patch would produce an
+	// invalid query
+	var static string
+	if len(staticSetParts) > 0 {
+		static = fmt.Sprintf("\tsetParts = append(setParts, %s)\n", strconv.Quote(strings.Join(staticSetParts, ", ")))
+	} else {
+		g.AddImport("errors")
+		static = "\tif len(setParts) == 0 {\n\t\treturn nil, errors.New(\"no fields to patch\")\n\t}\n"
+	}
+
+	params, args := g.primaryParams()
+	var wheres string
+	for i, k := range generator.SortedOffsets(g.PrimaryFields) {
+		wheres += fmt.Sprintf(patchWhereTmpl, args[i], g.FieldDBName(k))
+	}
+
+	var staleCheck string
+	if g.versionFieldOffset != -1 {
+		versionField := g.Struct.Field(g.versionFieldOffset)
+		params += ", " + generator.ParamName(versionField.Name()) + " " + g.TypeName(versionField.Type())
+		wheres += fmt.Sprintf(patchWhereTmpl, generator.ParamName(versionField.Name()), g.FieldDBName(g.versionFieldOffset))
+
+		g.AddImport("database/sql")
+		staleCheck = fmt.Sprintf("\tif err == sql.ErrNoRows {\n\t\treturn nil, %s\n\t}\n", g.generateStaleError())
+	}
+
+	g.Printf(patchTmpl,
+		g.funcSuffix(),
+		g.StructName,
+		fields,
+		g.CtxParam(),
+		params,
+		sets,
+		static,
+		wheres,
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.tableExpr(),
+		strconv.Quote(g.softDeleteWhere()+" RETURNING "+strings.Join(g.ReadFieldDBNames(""), ", ")),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		staleCheck,
+		g.dbParam(generator.TypeQueryRower),
+		g.StructType(),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
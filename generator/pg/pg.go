@@ -1,315 +1,273 @@
 package pg
 
 import (
-	"bytes"
 	"fmt"
-	"go/format"
 	"go/types"
 	"io"
-	"reflect"
-	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/pengux/cruder/generator"
 )
 
-const (
-	defaultPrimaryFieldName    = "ID"
-	defaultSoftDeleteFieldName = "DeletedAt"
-)
-
 type (
 	// PG generates the CRUD methods for Postgresql using lib/pg.
 	PG struct {
-		pkg                   *types.Package
-		t                     *types.Struct
-		structModel           string
-		header, body          bytes.Buffer // Accumulated output.
-		existingTypes         []cruderType
-		TableName             string
-		PkgName               string
-		SkipSuffix            bool
-		readFields            map[int]string
-		writeFields           map[int]string
-		primaryFieldOffset    int
-		softDeleteFieldOffset int
-		sqlImportAdded        bool
-
-		mx      sync.Mutex
-		imports map[string]bool
+		*generator.Base
+		existingTypes      []string
+		ConflictDoNothing  bool
+		CopyIn             bool
+		GetManyMap         bool
+		Repository         bool // Generate methods of a repository instead of functions
+		UntypedPrimaryKey  bool
+		SoftDeleteStrategy SoftDeleteStrategy
+		SoftDeleteValue    string
+		conflictFields     map[int]string
+		omitEmptyFields    map[int]string
+		jsonFields         map[int]string
+		cursorFieldOffset  int
+		versionFieldOffset int
 	}
+
+	// dialect contains the SQL of Postgresql needed by generator.Base
+	dialect struct{}
 )
 
-// New returns a PG
+// New returns a PG. Besides the options of generator.NewBase, the fields of the
+// struct can be configured in the "cruder" struct tag with:
+//
+//	pk          tag multiple fields for a composite primary key, the tagged fields are inserted by create
+//	omitempty   DEFAULT is inserted instead of the zero value of the field in create and upsert
+//	type=jsonb  the field is stored as JSON, type=json is also accepted
+//	conflict    the field is used as conflict target in upserts
+//	version     the field is used for optimistic locking
 func New(pkg *types.Package, t *types.Struct, structModel string) (*PG, error) {
 	gen := &PG{
-		pkg:                   pkg,
-		t:                     t,
-		structModel:           structModel,
-		TableName:             structModel,
-		PkgName:               pkg.Name(),
-		readFields:            make(map[int]string, t.NumFields()),
-		writeFields:           make(map[int]string, t.NumFields()),
-		softDeleteFieldOffset: -1, // -1 disable soft deletion
-		imports:               make(map[string]bool),
+		Base:               generator.NewBase(pkg, t, structModel, dialect{}),
+		conflictFields:     make(map[int]string),
+		omitEmptyFields:    make(map[int]string),
+		jsonFields:         make(map[int]string),
+		cursorFieldOffset:  -1, // -1 means paginate on the primary key only
+		versionFieldOffset: -1, // -1 disable optimistic locking
 	}
 
-	for i := 0; i < gen.t.NumFields(); i++ {
-		// If the defaultSoftDeleteFieldName exists in the struct, use it
-		// for soft deletion. Also don't include it in readFields or writeFields
-		if defaultSoftDeleteFieldName == gen.t.Field(i).Name() {
-			gen.softDeleteFieldOffset = i
+	for i := 0; i < t.NumFields(); i++ {
+		if gen.SkipField(i) {
 			continue
 		}
 
-		gen.readFields[i] = gen.t.Field(i).Name()
-
-		// If the defaultPrimaryFieldName exists in the struct, use it
-		// as primary key. Also don't include it in writeFields
-		if defaultPrimaryFieldName == gen.t.Field(i).Name() {
-			gen.primaryFieldOffset = i
-			continue
+		if gen.FieldHasTag(i, "version") {
+			gen.versionFieldOffset = i
 		}
-		gen.writeFields[i] = gen.t.Field(i).Name()
-	}
 
-	return gen, nil
-}
-
-// Generate generates CRUD code for the passed in functions
-func (g *PG) Generate(w io.Writer, fns ...generator.Function) error {
-	return nil
-}
-
-// SetReadFields sets the fields that should be returned in reading operations.
-// The passed in slice will be match against the fieldnames of the struct
-func (g *PG) SetReadFields(fields []string) error {
-	fieldNames := make(map[int]string)
-	for _, f := range fields {
-		found := false
-		for i := 0; i < g.t.NumFields(); i++ {
-			if strings.TrimSpace(f) == g.t.Field(i).Name() {
-				fieldNames[i] = g.t.Field(i).Name()
-				found = true
-				break
-			}
+		if gen.FieldHasTag(i, "conflict") {
+			gen.conflictFields[i] = t.Field(i).Name()
+		}
 
+		if gen.FieldHasTag(i, "omitempty") {
+			gen.omitEmptyFields[i] = t.Field(i).Name()
 		}
 
-		if !found {
-			return fmt.Errorf("the field %s does not exists in struct %s", f, g.structModel)
+		switch typ := gen.FieldTagValue(i, "type"); typ {
+		case "":
+		case "json", "jsonb":
+			gen.jsonFields[i] = t.Field(i).Name()
+		default:
+			return nil, fmt.Errorf("unknown type %s in the cruder tag of field %s", typ, t.Field(i).Name())
 		}
 	}
 
-	g.readFields = fieldNames
-	return nil
+	return gen, nil
 }
 
-// SetWriteFields sets the fields that should be returned in writing operations.
-// The passed in slice will be match against the fieldnames of the struct
-func (g *PG) SetWriteFields(fields []string) error {
-	fieldNames := make(map[int]string)
-	for _, f := range fields {
-		found := false
-		for i := 0; i < g.t.NumFields(); i++ {
-			if strings.TrimSpace(f) == g.t.Field(i).Name() {
-				fieldNames[i] = g.t.Field(i).Name()
-				found = true
-				break
-			}
+// Generate generates CRUD code for the passed in functions and writes the
+// formatted file, including the package header, to w
+func (g *PG) Generate(w io.Writer, fns ...generator.Function) error {
+	if g.ExternalPkg {
+		if err := g.CheckExportedFields(g.versionFieldOffset, g.cursorFieldOffset); err != nil {
+			return err
+		}
+	}
 
+	for _, fn := range fns {
+		var err error
+		switch fn {
+		case generator.Create:
+			err = g.GenerateCreate()
+		case generator.Get:
+			err = g.GenerateGet()
+		case generator.List:
+			err = g.GenerateList()
+		case generator.Update:
+			err = g.GenerateUpdate()
+		case generator.Delete:
+			err = g.GenerateDelete()
+		case generator.Upsert:
+			err = g.GenerateUpsert()
+		case generator.CreateMany:
+			err = g.GenerateCreateMany()
+		case generator.GetMany:
+			err = g.GenerateGetMany()
+		case generator.Count:
+			err = g.GenerateCount()
+		case generator.Exists:
+			err = g.GenerateExists()
+		case generator.ListAfter:
+			err = g.GenerateListAfter()
+		case generator.Sorter:
+			err = g.GenerateSorter()
+		case generator.Filter:
+			err = g.GenerateFilter()
+		case generator.Restore:
+			err = g.GenerateRestore()
+		case generator.HardDelete:
+			err = g.GenerateHardDelete()
+		case generator.ListDeleted:
+			err = g.GenerateListDeleted()
+		case generator.Purge:
+			err = g.GeneratePurge()
+		case generator.Patch:
+			err = g.GeneratePatch()
+		default:
+			return fmt.Errorf("unknown function %s", fn)
 		}
-		if !found {
-			return fmt.Errorf("the field %s does not exists in struct %s", f, g.structModel)
+		if err != nil {
+			return fmt.Errorf("generating %s: %s", fn, err)
 		}
 	}
 
-	g.writeFields = fieldNames
-	return nil
-}
-
-// SetPrimaryField sets the field that are used as primary key in lookups
-func (g *PG) SetPrimaryField(f string) error {
-	for i := 0; i < g.t.NumFields(); i++ {
-		if strings.TrimSpace(f) == g.t.Field(i).Name() {
-			g.primaryFieldOffset = i
-
-			return nil
+	if g.Repository {
+		if err := g.generateRepository(); err != nil {
+			return fmt.Errorf("generating repository: %s", err)
 		}
-
 	}
 
-	return fmt.Errorf("the field %s does not exists in struct %s", f, g.structModel)
+	return g.Write(w)
 }
 
-// SetSoftDeleteField sets the field that should be used for soft deletion.
-// The field should be of type nullable datetime but this function does not check that.
-func (g *PG) SetSoftDeleteField(f string) error {
-	for i := 0; i < g.t.NumFields(); i++ {
-		if strings.TrimSpace(f) == g.t.Field(i).Name() {
-			g.softDeleteFieldOffset = i
-
-			return nil
-		}
-
+// SetCursorField sets the field that entries are sorted by, before the primary
+// field, in cursor pagination. The field should not be nullable
+func (g *PG) SetCursorField(f string) error {
+	i, err := g.FieldOffset(f)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("the field %s does not exists in struct %s", f, g.structModel)
+	g.cursorFieldOffset = i
+	return nil
 }
 
-// readFieldNames returns a slice of the read field names.
-// A prefix can be passed which would be added before each name.
-func (g *PG) readFieldNames(prefix string) []string {
-	var fieldNames []string
-	// Ordered iteration of the map
-	var keys []int
-	for k := range g.readFields {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		fieldNames = append(fieldNames, prefix+g.readFields[k])
+// SetConflictFields sets the fields that are used as conflict target in
+// upserts. Default to the primary field
+func (g *PG) SetConflictFields(fields []string) error {
+	fieldNames, err := g.MatchFields(fields)
+	if err != nil {
+		return err
 	}
 
-	return fieldNames
+	g.conflictFields = fieldNames
+	return nil
 }
 
-// readFieldDBNames returns a slice of the read field names, but in their DB forms (if any).
-// The DB form is taken from the "db" struct tag if defined, or it will be the same as the field
-// name. A prefix can be passed which would be added before each name.
-func (g *PG) readFieldDBNames(prefix string) []string {
+// ReadFieldNames returns a slice of the read field names, wrapped in
+// cruderJSONB for the fields stored as JSON.
+// A prefix can be passed which would be added before each name.
+func (g *PG) ReadFieldNames(prefix string) []string {
 	var fieldNames []string
-	// Ordered iteration of the map
-	var keys []int
-	for k := range g.readFields {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		fieldNames = append(fieldNames, prefix+g.fieldDBName(k))
+	for _, k := range generator.SortedOffsets(g.ReadFields) {
+		fieldNames = append(fieldNames, g.jsonArg(k, prefix+g.ReadFields[k]))
 	}
 
 	return fieldNames
 }
 
-// writeFieldNames returns a slice of the write field names.
+// WriteFieldNames returns a slice of the write field names as arguments for a query,
+// except the timestamp fields set by the database.
 // A prefix can be passed which would be added before each name.
-func (g *PG) writeFieldNames(prefix string) []string {
-	var fieldNames []string
-	// Ordered iteration of the map
-	var keys []int
-	for k := range g.writeFields {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		name := prefix + g.writeFields[k]
+func (g *PG) WriteFieldNames(prefix string) []string {
+	return g.FieldArgs(g.ValueFields(g.WriteFields), prefix)
+}
 
-		// If the field is a struct, then return a pointer expression
-		if _, ok := g.t.Field(k).Type().(*types.Named); ok {
-			name = "&" + name
+// FieldArgs returns the arguments of generator.Base.FieldArgs, with the
+// fields stored as JSON wrapped in cruderJSONB
+func (g *PG) FieldArgs(fields map[int]string, prefix string) []string {
+	args := g.Base.FieldArgs(fields, prefix)
+	for i, k := range generator.SortedOffsets(fields) {
+		if _, ok := g.jsonFields[k]; ok {
+			args[i] = g.jsonArg(k, "&"+prefix+fields[k])
 		}
-		fieldNames = append(fieldNames, name)
 	}
 
-	return fieldNames
+	return args
 }
 
-// writeFieldDBNames returns a slice of the read field names, but in their DB forms (if any).
-// The DB form is taken from the "db" struct tag if defined, or it will be the same as the field
-// name. A prefix can be passed which would be added before each name.
-func (g *PG) writeFieldDBNames(prefix string) []string {
-	var fieldNames []string
-	// Ordered iteration of the map
-	var keys []int
-	for k := range g.writeFields {
-		keys = append(keys, k)
-	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		fieldNames = append(fieldNames, prefix+g.fieldDBName(k))
+// jsonArg returns the expression wrapping a pointer to the field in
+// cruderJSONB if the field is stored as JSON, otherwise the expression itself
+func (g *PG) jsonArg(k int, expr string) string {
+	if _, ok := g.jsonFields[k]; !ok {
+		return expr
 	}
 
-	return fieldNames
+	return "cruderJSONB{" + expr + "}"
 }
 
-// placeholderStrings returns a slice of strings for n placeholders
-func (g *PG) placeholderStrings(n int) []string {
-	var placeholders []string
-	for i := 1; i <= n; i++ {
-		placeholders = append(placeholders, "$"+strconv.Itoa(i))
+// primaryWhere returns the condition matching the primary fields, with the
+// placeholders numbered from n
+func (g *PG) primaryWhere(n int) string {
+	var conds []string
+	for i, f := range g.FieldDBNames(g.PrimaryFields, "") {
+		conds = append(conds, fmt.Sprintf("%s = $%d", f, n+i))
 	}
 
-	return placeholders
+	return strings.Join(conds, " AND ")
 }
 
-// HeaderPrintf writes the input to the PG's header buffer
-func (g *PG) HeaderPrintf(in string, args ...interface{}) {
-	fmt.Fprintf(&g.header, in, args...)
-}
-
-// Printf writes the input to the PG's body buffer
-func (g *PG) Printf(in string, args ...interface{}) {
-	fmt.Fprintf(&g.body, in, args...)
-}
-
-func (g *PG) pkgDecl() []byte {
-	return []byte(fmt.Sprintf("package %s\n", g.PkgName))
-}
+// primaryParams returns the parameters of the primary fields for the signature
+// of generated functions and the names of the parameters. A single primary
+// field is passed as id, while composite primary keys are passed as one
+// parameter per field. The parameters have the types of the fields unless
+// UntypedPrimaryKey is set
+func (g *PG) primaryParams() (string, []string) {
+	var params, names []string
+	for _, k := range generator.SortedOffsets(g.PrimaryFields) {
+		name := "id"
+		if len(g.PrimaryFields) > 1 {
+			name = generator.ParamName(g.PrimaryFields[k])
+		}
 
-func (g *PG) importsDecl() []byte {
-	var imports []string
-	for i := range g.imports {
-		imports = append(imports, "\""+i+"\"")
+		typeName := "interface{}"
+		if !g.UntypedPrimaryKey {
+			typeName = g.TypeName(g.Struct.Field(k).Type())
+		}
+		params = append(params, name+" "+typeName)
+		names = append(names, name)
 	}
 
-	return []byte(fmt.Sprintf(`
-import (
-	%s
-)
-`, strings.Join(imports, "\n\t")))
-}
-
-// Format returns the gofmt-ed contents of the PG's buffer.
-func (g *PG) Format() ([]byte, error) {
-	return format.Source(append(append(g.pkgDecl(), g.importsDecl()...), append(g.header.Bytes(), g.body.Bytes()...)...))
-}
-
-// String output all buffers as string
-func (g *PG) String() string {
-	return string(g.pkgDecl()) + string(g.importsDecl()) + g.header.String() + g.body.String()
+	return strings.Join(params, ", "), names
 }
 
-func (g *PG) typeExist(t cruderType) bool {
+func (g *PG) typeExist(t string) bool {
 	for _, x := range g.existingTypes {
 		if x == t {
 			return true
 		}
 	}
 
-	// Check if type already exist in package
-	return g.pkg.Scope().Lookup(string(t)) != nil
+	return false
 }
 
-func (g *PG) addImport(pkg string) {
-	g.mx.Lock()
-	g.imports[pkg] = true
-	g.mx.Unlock()
+// QuoteIdentifier returns the name unquoted, as the generated queries use
+// unquoted identifiers
+func (dialect) QuoteIdentifier(name string) string {
+	return name
 }
 
-// Return the name of the field in their DB form. The DB form is taken from the
-// "db" struct tag if defined, otherwise the field name.
-func (g *PG) fieldDBName(i int) string {
-	if i >= g.t.NumFields() {
-		return ""
-	}
-
-	st := reflect.StructTag(g.t.Tag(i))
-	if st.Get("db") != "" {
-		return st.Get("db")
-	}
+// Placeholder returns $n
+func (dialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
 
-	return g.t.Field(i).Name()
+// Now returns NOW()
+func (dialect) Now() string {
+	return "NOW()"
 }
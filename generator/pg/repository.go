@@ -0,0 +1,156 @@
+package pg
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	repositoryTmpl = `
+// %[1]sRepository stores %[1]s entries in the table Table of DB
+type %[1]sRepository struct {
+	DB    %[2]s
+	Table string
+}
+
+// New%[1]sRepository returns a %[1]sRepository using the table %[3]s
+func New%[1]sRepository(db %[2]s) *%[1]sRepository {
+	return &%[1]sRepository{DB: db, Table: %[4]s}
+}
+
+// %[1]sStore contains the methods of %[1]sRepository, to be used in place of the
+// repository where it needs to be replaced, e.g. in tests
+type %[1]sStore interface {
+%[5]s}
+
+var _ %[1]sStore = (*%[1]sRepository)(nil)
+`
+
+	// repositoryReceiver is the name of the receiver of the repository
+	// methods, which must not clash with the variables of the templates
+	repositoryReceiver = "repo"
+)
+
+// repositoryName returns the name of the repository type of the struct
+func (g *PG) repositoryName() string {
+	return g.StructName + "Repository"
+}
+
+// receiver returns the receiver of the generated functions, which are methods
+// of the repository in repository mode
+func (g *PG) receiver() string {
+	if !g.Repository {
+		return ""
+	}
+
+	return fmt.Sprintf("(%s *%s) ", repositoryReceiver, g.repositoryName())
+}
+
+// funcSuffix returns the suffix of the generated functions, which is the
+// struct name unless SkipSuffix is set. Methods of the repository have none
+func (g *PG) funcSuffix() string {
+	if g.SkipSuffix || g.Repository {
+		return ""
+	}
+
+	return g.StructName
+}
+
+// dbParam returns the db parameter of the generated functions, with the
+// helper type t. Methods of the repository use its DB instead
+func (g *PG) dbParam(t string) string {
+	if g.Repository {
+		return ""
+	}
+
+	return "db " + g.HelperType(t) + ", "
+}
+
+// dbExpr returns the expression of the database handle in the generated
+// functions
+func (g *PG) dbExpr() string {
+	if g.Repository {
+		return repositoryReceiver + ".DB"
+	}
+
+	return "db"
+}
+
+// tableName returns the table to use inside the raw string literals of the
+// generated SQL. The table of a repository is set at runtime, so the literal
+// is closed to add it
+func (g *PG) tableName() string {
+	if g.Repository {
+		return "` + " + repositoryReceiver + ".Table + `"
+	}
+
+	return g.TableName
+}
+
+// tableExpr returns the table as a Go expression of the generated code
+func (g *PG) tableExpr() string {
+	if g.Repository {
+		return repositoryReceiver + ".Table"
+	}
+
+	return strconv.Quote(g.TableName)
+}
+
+// isRepositoryMethod reports whether fn is a method of the repository, the
+// other generated types, e.g. the filter, have methods too
+func (g *PG) isRepositoryMethod(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return false
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+
+	return ok && ident.Name == g.repositoryName()
+}
+
+// generateRepository adds the repository type, its constructor and the
+// interface of its methods to the header buffer. The methods are taken from
+// the code generated so far
+func (g *PG) generateRepository() error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", g.String(), 0)
+	if err != nil {
+		return fmt.Errorf("parsing the generated methods: %s", err)
+	}
+
+	var methods bytes.Buffer
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || !g.isRepositoryMethod(fn) {
+			continue
+		}
+
+		var signature bytes.Buffer
+		err = printer.Fprint(&signature, fset, fn.Type)
+		if err != nil {
+			return err
+		}
+		// Drop the func keyword
+		fmt.Fprintf(&methods, "\t%s%s\n", fn.Name.Name, bytes.TrimPrefix(signature.Bytes(), []byte("func")))
+	}
+
+	g.HeaderPrintf(repositoryTmpl,
+		g.StructName,
+		g.HelperType(generator.TypeDB),
+		g.TableName,
+		strconv.Quote(g.TableName),
+		methods.String(),
+	)
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package pg
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+// SoftDeleteStrategy is the way entries are marked as deleted in the soft
+// delete field
+type SoftDeleteStrategy string
+
+const (
+	// SoftDeleteTimestamp sets a nullable time field to the time of deletion
+	SoftDeleteTimestamp SoftDeleteStrategy = "timestamp"
+	// SoftDeleteBool sets a boolean field to true
+	SoftDeleteBool SoftDeleteStrategy = "bool"
+	// SoftDeleteStatus sets a string field to SoftDeleteValue
+	SoftDeleteStatus SoftDeleteStrategy = "status"
+
+	defaultSoftDeleteValue = "deleted"
+)
+
+const (
+	restoreTmpl = `
+// Restore%[1]s restores a soft deleted entry in DB
+func %[9]sRestore%[1]s(%[2]s%[8]s%[6]s) error {
+	result, err := %[10]s.%[3]s(
+		%[4]s` + "`" + `%[5]s` + "`" + `,
+		%[7]s,
+	)
+	if err != nil {
+		return err
+	}
+
+	if r, err := result.RowsAffected(); err != nil || r == 0 {
+		if err != nil {
+			return err
+		}
+		return errors.New("sql: no rows affected")
+	}
+
+	return nil
+}
+`
+
+	hardDeleteTmpl = `
+// HardDelete%[1]s deletes an entry from DB, whether it is soft deleted or not
+func %[9]sHardDelete%[1]s(%[2]s%[8]s%[6]s) error {
+	result, err := %[10]s.%[3]s(
+		%[4]s` + "`" + `%[5]s` + "`" + `,
+		%[7]s,
+	)
+	if err != nil {
+		return err
+	}
+
+	if r, err := result.RowsAffected(); err != nil || r == 0 {
+		if err != nil {
+			return err
+		}
+		return errors.New("sql: no rows affected")
+	}
+
+	return nil
+}
+`
+
+	purgeTmpl = `
+// Purge%[1]s deletes the entries soft deleted before olderThan from DB and
+// returns the number of deleted entries
+func %[7]sPurge%[1]s(%[2]s%[6]solderThan time.Time) (int64, error) {
+	result, err := %[8]s.%[3]s(
+		%[4]s` + "`" + `%[5]s` + "`" + `,
+		olderThan,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+`
+)
+
+// GenerateRestore generates the Restore method for the struct
+func (g *PG) GenerateRestore() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+	if err := g.RequireSoftDeleteField(); err != nil {
+		return err
+	}
+
+	set, err := g.softDeleteSet(false)
+	if err != nil {
+		return err
+	}
+
+	g.AddImport("errors")
+
+	params, args := g.primaryParams()
+
+	g.Printf(restoreTmpl,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.CtxArg(),
+		fmt.Sprintf("UPDATE %s SET %s WHERE %s AND %s",
+			g.tableName(),
+			set,
+			g.primaryWhere(1),
+			g.softDeleteCond("", true),
+		),
+		params,
+		strings.Join(args, ", "),
+		g.dbParam(generator.TypeExecer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// GenerateHardDelete generates the HardDelete method for the struct
+func (g *PG) GenerateHardDelete() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+	if err := g.RequireSoftDeleteField(); err != nil {
+		return err
+	}
+
+	g.AddImport("errors")
+
+	params, args := g.primaryParams()
+
+	g.Printf(hardDeleteTmpl,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.CtxArg(),
+		fmt.Sprintf("DELETE FROM %s WHERE %s",
+			g.tableName(),
+			g.primaryWhere(1),
+		),
+		params,
+		strings.Join(args, ", "),
+		g.dbParam(generator.TypeExecer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// GenerateListDeleted generates the ListDeleted method for the struct, which
+// works like List but only returns soft deleted entries
+func (g *PG) GenerateListDeleted() error {
+	if err := g.RequireSoftDeleteField(); err != nil {
+		return err
+	}
+
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	g.Printf(listTmpl,
+		g.listSuffix(),
+		g.StructType(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.tableName(),
+		g.softDeleteWhereCode(true),
+		strings.Join(g.ReadFieldNames("&e."), ", "),
+		g.CtxParam(),
+		g.DBMethod("Query"),
+		g.CtxArg(),
+		"ListDeleted",
+		"soft deleted ",
+		g.dbParam(generator.TypeQueryer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// GeneratePurge generates the Purge method for the struct, which permanently
+// deletes the entries soft deleted before a given time
+func (g *PG) GeneratePurge() error {
+	if err := g.RequireSoftDeleteField(); err != nil {
+		return err
+	}
+	if s := g.softDeleteStrategy(); s != SoftDeleteTimestamp {
+		return fmt.Errorf("purge needs the time of deletion, which the %s soft delete strategy does not store", s)
+	}
+
+	g.AddImport("time")
+
+	g.Printf(purgeTmpl,
+		g.funcSuffix(),
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.CtxArg(),
+		fmt.Sprintf("DELETE FROM %s WHERE %s < $1",
+			g.tableName(),
+			g.FieldDBName(g.SoftDeleteFieldOffset),
+		),
+		g.dbParam(generator.TypeExecer),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
+
+// SetSoftDeleteStrategy sets the way entries are marked as deleted. Default to
+// a strategy matching the type of the soft delete field
+func (g *PG) SetSoftDeleteStrategy(s string) error {
+	switch strategy := SoftDeleteStrategy(s); strategy {
+	case SoftDeleteTimestamp, SoftDeleteBool, SoftDeleteStatus:
+		g.SoftDeleteStrategy = strategy
+		return nil
+	}
+
+	return fmt.Errorf("unknown soft delete strategy %s, should be one of %s, %s or %s", s, SoftDeleteTimestamp, SoftDeleteBool, SoftDeleteStatus)
+}
+
+// softDeleteStrategy returns SoftDeleteStrategy if it is set, otherwise the
+// strategy matching the type of the soft delete field: bool for booleans,
+// status for strings and timestamp for everything else. Pointers and the
+// sql.NullBool and sql.NullString wrappers are matched by the wrapped type, as
+// the conditions of the strategies treat NULL as not deleted
+func (g *PG) softDeleteStrategy() SoftDeleteStrategy {
+	if g.SoftDeleteStrategy != "" {
+		return g.SoftDeleteStrategy
+	}
+
+	t := g.Struct.Field(g.SoftDeleteFieldOffset).Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "database/sql" {
+		switch n.Obj().Name() {
+		case "NullBool":
+			return SoftDeleteBool
+		case "NullString":
+			return SoftDeleteStatus
+		}
+	}
+
+	if t, ok := t.Underlying().(*types.Basic); ok {
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return SoftDeleteBool
+		case t.Info()&types.IsString != 0:
+			return SoftDeleteStatus
+		}
+	}
+
+	return SoftDeleteTimestamp
+}
+
+// softDeleteValue returns the value of the soft delete field for deleted
+// entries with the status strategy, as a SQL string literal
+func (g *PG) softDeleteValue() string {
+	v := g.SoftDeleteValue
+	if v == "" {
+		v = defaultSoftDeleteValue
+	}
+
+	return "'" + strings.Replace(v, "'", "''", -1) + "'"
+}
+
+// softDeleteCond returns the condition matching either deleted or not deleted
+// entries, with the soft delete field prefixed by prefix
+func (g *PG) softDeleteCond(prefix string, deleted bool) string {
+	field := prefix + g.FieldDBName(g.SoftDeleteFieldOffset)
+
+	switch g.softDeleteStrategy() {
+	case SoftDeleteBool:
+		if deleted {
+			return field + " IS TRUE"
+		}
+		return field + " IS NOT TRUE"
+	case SoftDeleteStatus:
+		if deleted {
+			return field + " = " + g.softDeleteValue()
+		}
+		return field + " IS DISTINCT FROM " + g.softDeleteValue()
+	}
+
+	if deleted {
+		return field + " IS NOT NULL"
+	}
+	return field + " IS NULL"
+}
+
+// softDeleteWhere returns the condition excluding deleted entries, to append
+// to a WHERE clause, or an empty string if no soft delete field is set
+func (g *PG) softDeleteWhere() string {
+	if g.SoftDeleteFieldOffset == -1 {
+		return ""
+	}
+
+	return " AND " + g.softDeleteCond("", false)
+}
+
+// softDeleteSet returns the assignment of the soft delete field which marks an
+// entry as deleted, or as not deleted when restoring it
+func (g *PG) softDeleteSet(deleted bool) (string, error) {
+	field := g.FieldDBName(g.SoftDeleteFieldOffset)
+
+	switch g.softDeleteStrategy() {
+	case SoftDeleteBool:
+		if deleted {
+			return field + " = TRUE", nil
+		}
+		return field + " = FALSE", nil
+	case SoftDeleteStatus:
+		if deleted {
+			return field + " = " + g.softDeleteValue(), nil
+		}
+		return "", fmt.Errorf("the status before deletion is unknown with the %s soft delete strategy", SoftDeleteStatus)
+	}
+
+	if deleted {
+		return field + " = NOW()", nil
+	}
+	return field + " = NULL", nil
+}
@@ -0,0 +1,146 @@
+package pg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	sorterTmpl = `
+// %[1]sSortColumn is a column that %[1]s entries can be sorted by
+type %[1]sSortColumn string
+
+// Columns that %[1]s entries can be sorted by
+const (
+%[2]s)
+
+// Valid reports whether the column is one of the %[1]sSortColumn constants
+func (c %[1]sSortColumn) Valid() bool {
+	switch c {
+	case %[3]s:
+		return true
+	}
+
+	return false
+}
+
+// %[1]sSortNulls sets where NULL values are placed when sorting
+type %[1]sSortNulls int
+
+// Placements of NULL values, the default is NULLS LAST for ascending order and NULLS FIRST
+// for descending order
+const (
+	%[1]sSortNullsDefault %[1]sSortNulls = iota
+	%[1]sSortNullsFirst
+	%[1]sSortNullsLast
+)
+
+// %[1]sSortField sorts %[1]s entries by a single column
+type %[1]sSortField struct {
+	Column %[1]sSortColumn
+	Desc   bool
+	Nulls  %[1]sSortNulls
+}
+
+// %[1]sSort is a sort order of %[1]s entries that only accepts known columns. It can be
+// passed as sorter to the List functions
+type %[1]sSort []%[1]sSortField
+
+// OrderBy returns the ORDER BY expression of the sort order. Fields with an invalid column
+// are left out
+func (s %[1]sSort) OrderBy() string {
+	parts := make([]string, 0, len(s))
+	for _, f := range s {
+		if !f.Column.Valid() {
+			continue
+		}
+
+		part := string(f.Column) + " ASC"
+		if f.Desc {
+			part = string(f.Column) + " DESC"
+		}
+		switch f.Nulls {
+		case %[1]sSortNullsFirst:
+			part += " NULLS FIRST"
+		case %[1]sSortNullsLast:
+			part += " NULLS LAST"
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Parse%[1]sSort parses a comma separated list of columns, e.g. "name,-created_at:nullslast".
+// Columns prefixed with "-" are sorted in descending order, and with "+" or no prefix in
+// ascending order. A column can be suffixed with ":nullsfirst" or ":nullslast". An error
+// is returned for unknown columns
+func Parse%[1]sSort(s string) (%[1]sSort, error) {
+	var order %[1]sSort
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var f %[1]sSortField
+		if i := strings.LastIndex(part, ":"); i != -1 {
+			switch strings.ToLower(part[i+1:]) {
+			case "nullsfirst":
+				f.Nulls = %[1]sSortNullsFirst
+			case "nullslast":
+				f.Nulls = %[1]sSortNullsLast
+			default:
+				return nil, fmt.Errorf("unknown NULL placement in %%q", part)
+			}
+			part = part[:i]
+		}
+
+		switch {
+		case strings.HasPrefix(part, "-"):
+			f.Desc = true
+			part = part[1:]
+		case strings.HasPrefix(part, "+"):
+			part = part[1:]
+		}
+
+		f.Column = %[1]sSortColumn(part)
+		if !f.Column.Valid() {
+			return nil, fmt.Errorf("unknown sort column %%q", part)
+		}
+		order = append(order, f)
+	}
+
+	return order, nil
+}
+`
+)
+
+// GenerateSorter generates a sort type for the struct with a constant for every
+// read field, which can be used as sorter in List
+func (g *PG) GenerateSorter() error {
+	if len(g.ReadFields) == 0 {
+		return fmt.Errorf("no read fields to sort by")
+	}
+
+	g.AddImport("fmt")
+	g.AddImport("strings")
+
+	var consts, names []string
+	for _, k := range generator.SortedOffsets(g.ReadFields) {
+		name := g.StructName + "SortBy" + g.ReadFields[k]
+		consts = append(consts, fmt.Sprintf("\t%s %sSortColumn = %s\n", name, g.StructName, strconv.Quote(g.FieldDBName(k))))
+		names = append(names, name)
+	}
+
+	g.Printf(sorterTmpl,
+		g.StructName,
+		strings.Join(consts, ""),
+		strings.Join(names, ", "),
+	)
+
+	return nil
+}
@@ -3,53 +3,74 @@ package pg
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pengux/cruder/generator"
 )
 
 const (
 	updateTmpl = `
 // Update%[1]s updates an entry into DB
-func Update%[1]s(db cruderQueryRower, x %[2]s) (*%[2]s, error) {
+func %[15]sUpdate%[1]s(%[3]s%[14]sx %[2]s) (*%[2]s, error) {
 	var y %[2]s
-	err := db.QueryRow(
-		` + "`" + `UPDATE %s SET %s WHERE %s = $%d%s
-		RETURNING %s` + "`" + `,
-		%s,
-	).Scan(%s)
-
+	err := %[16]s.%[4]s(
+		%[5]s` + "`" + `UPDATE %[6]s SET %[7]s WHERE %[8]s%[9]s
+		RETURNING %[10]s` + "`" + `,
+		%[11]s,
+	).Scan(%[12]s)
+%[13]s
 	return &y, err
 }
 `
 )
 
 // GenerateUpdate generates the Update method for the struct
-func (g *PG) GenerateUpdate() {
-	g.GenerateType(typeQueryRowerInterface)
+func (g *PG) GenerateUpdate() error {
+	if err := g.RequirePrimaryField(); err != nil {
+		return err
+	}
+
+	// The version field is incremented instead of being set from the struct
+	writeFields := g.ValueFields(g.WriteFields)
+	delete(writeFields, g.versionFieldOffset)
 
 	var setParts []string
-	for i, f := range g.writeFieldDBNames("") {
+	for i, f := range g.FieldDBNames(writeFields, "") {
 		setParts = append(setParts, fmt.Sprintf("%s = $%d", f, i+1))
 	}
+	n := len(setParts)
+	setParts = append(setParts, g.TimestampSetParts()...)
 
-	var softDeleteWhere string
-	if g.softDeleteFieldOffset != -1 {
-		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.fieldDBName(g.softDeleteFieldOffset))
-	}
+	where := g.primaryWhere(n + 1)
+	args := append(g.FieldArgs(writeFields, "x."), g.FieldNames(g.PrimaryFields, "x.")...)
+
+	var staleCheck string
+	if g.versionFieldOffset != -1 {
+		setParts = append(setParts, g.versionSet(""))
+		where += g.versionWhere(len(args) + 1)
+		args = append(args, "x."+g.Struct.Field(g.versionFieldOffset).Name())
 
-	var suffix string
-	if !g.SkipSuffix {
-		suffix = g.structModel
+		g.AddImport("database/sql")
+		staleCheck = fmt.Sprintf("\tif err == sql.ErrNoRows {\n\t\treturn nil, %s\n\t}\n", g.generateStaleError())
 	}
 
 	g.Printf(updateTmpl,
-		suffix,
-		g.structModel,
-		g.TableName,
+		g.funcSuffix(),
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.tableName(),
 		strings.Join(setParts, ", "),
-		g.fieldDBName(g.primaryFieldOffset),
-		len(setParts),
-		softDeleteWhere,
-		strings.Join(g.readFieldDBNames(""), ", "),
-		strings.Join(append(g.writeFieldNames("x."), "x."+g.t.Field(g.primaryFieldOffset).Name()), ", "),
-		strings.Join(g.readFieldNames("&y."), ","),
+		where,
+		g.softDeleteWhere(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		strings.Join(args, ", "),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		staleCheck,
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
 	)
+
+	return nil
 }
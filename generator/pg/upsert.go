@@ -0,0 +1,105 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	upsertTmpl = `
+// Upsert%[1]s %[3]s
+func %[17]sUpsert%[1]s(%[4]s%[16]sx %[2]s) (*%[2]s, error) {
+%[15]s	var y %[2]s
+	err := %[18]s.%[5]s(
+		%[6]s` + "`" + `INSERT INTO %[7]s (%[8]s) VALUES (%[9]s)
+		ON CONFLICT (%[10]s) %[11]s
+		RETURNING %[12]s` + "`" + `,
+		%[13]s,
+	).Scan(%[14]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateUpsert generates the Upsert method for the struct, which inserts an
+// entry or updates the write fields of the entry conflicting with it. A soft
+// deleted entry is not revived by an upsert
+func (g *PG) GenerateUpsert() error {
+	conflictFields := g.conflictFields
+	if len(conflictFields) == 0 {
+		if err := g.RequirePrimaryField(); err != nil {
+			return err
+		}
+		conflictFields = g.PrimaryFields
+	}
+
+	// The conflict fields must be inserted even if they are not write fields
+	insertFields := g.createFields()
+	for k, f := range conflictFields {
+		insertFields[k] = f
+	}
+	insertFields = g.ValueFields(insertFields)
+	values, args, code, err := g.insertValues(insertFields)
+	if err != nil {
+		return err
+	}
+
+	timestampColumns, _ := g.TimestampValues()
+	columns := append(g.FieldDBNames(insertFields, ""), timestampColumns...)
+
+	var setParts []string
+	for _, k := range generator.SortedOffsets(g.ValueFields(g.WriteFields)) {
+		if _, ok := conflictFields[k]; ok || k == g.versionFieldOffset {
+			continue
+		}
+		setParts = append(setParts, fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", g.FieldDBName(k)))
+	}
+	if len(setParts) > 0 {
+		setParts = append(setParts, g.TimestampSetParts()...)
+		if g.versionFieldOffset != -1 {
+			setParts = append(setParts, g.versionSet(g.tableName()+"."))
+		}
+	}
+
+	conflictTarget := strings.Join(g.FieldDBNames(conflictFields, ""), ", ")
+	doc := fmt.Sprintf("inserts an entry into DB, or updates the entry with the same %s if it already exists", conflictTarget)
+	conflictAction := "DO NOTHING"
+	if g.ConflictDoNothing || len(setParts) == 0 {
+		doc = fmt.Sprintf("inserts an entry into DB unless an entry with the same %s already exists,\n// in which case sql.ErrNoRows is returned", conflictTarget)
+	} else {
+		conflictAction = "DO UPDATE SET " + strings.Join(setParts, ", ")
+
+		// Soft deleted entries are not updated, like in Update, and RETURNING
+		// yields no row for them
+		if g.SoftDeleteFieldOffset != -1 {
+			conflictAction += " WHERE " + g.softDeleteCond(g.tableName()+".", false)
+			doc += ",\n// unless that entry is soft deleted, in which case it is left unchanged and sql.ErrNoRows is returned"
+		}
+	}
+
+	g.Printf(upsertTmpl,
+		g.funcSuffix(),
+		g.StructType(),
+		doc,
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.tableName(),
+		strings.Join(columns, ", "),
+		values,
+		conflictTarget,
+		conflictAction,
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		args,
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		code,
+		g.dbParam(generator.TypeQueryRower),
+		g.receiver(),
+		g.dbExpr(),
+	)
+
+	return nil
+}
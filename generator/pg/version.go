@@ -0,0 +1,54 @@
+package pg
+
+import "fmt"
+
+const (
+	staleErrTmpl = `
+// ErrStale%[1]s is returned when an entry could not be updated or deleted
+// because its version doesn't match, as it was changed by someone else since it
+// was read, or because it doesn't exist
+var ErrStale%[1]s = errors.New("stale %[2]s: the entry was changed or deleted since it was read")
+`
+)
+
+// SetVersionField sets the field that is used for optimistic locking. The
+// field should be an integer which is incremented on every update
+func (g *PG) SetVersionField(f string) error {
+	i, err := g.FieldOffset(f)
+	if err != nil {
+		return err
+	}
+
+	g.versionFieldOffset = i
+	return nil
+}
+
+// generateStaleError adds the error returned on version mismatches to the
+// header buffer, unless it already exists, and returns the name of the error
+func (g *PG) generateStaleError() string {
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	name := "ErrStale" + suffix
+	if !g.typeExist(name) {
+		g.AddImport("errors")
+		g.HeaderPrintf(staleErrTmpl, suffix, g.StructName)
+		g.existingTypes = append(g.existingTypes, name)
+	}
+
+	return name
+}
+
+// versionWhere returns the condition matching the version field with the
+// placeholder $n, to append to a WHERE clause
+func (g *PG) versionWhere(n int) string {
+	return fmt.Sprintf(" AND %s = $%d", g.FieldDBName(g.versionFieldOffset), n)
+}
+
+// versionSet returns the assignment incrementing the version field, which is
+// prefixed by prefix on the right hand side
+func (g *PG) versionSet(prefix string) string {
+	return fmt.Sprintf("%[1]s = %[2]s%[1]s + 1", g.FieldDBName(g.versionFieldOffset), prefix)
+}
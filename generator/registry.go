@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+type (
+	// Options contains the settings that are shared by all backends
+	Options struct {
+		// Pkg is the type-checked package containing the struct
+		Pkg *types.Package
+		// Struct is the struct to generate CRUD code for
+		Struct *types.Struct
+		// StructName is the name of the struct in Pkg
+		StructName string
+		// ExternalPkg is set when the code is generated into another package
+		// than Pkg. The struct and the types declared in Pkg are then
+		// qualified with the name of Pkg, which is imported
+		ExternalPkg bool
+		// CommandLine contains the arguments of cruder, which are shown in
+		// the header of the generated file
+		CommandLine string
+
+		PkgName         string
+		SkipSuffix      bool
+		NoContext       bool
+		ReadFields      []string
+		WriteFields     []string
+		PrimaryFields   []string
+		SoftDeleteField string
+	}
+
+	// Backend describes a generator that can be selected by name, e.g. from
+	// a cruder subcommand
+	Backend struct {
+		// Name is the name of the backend, also used as subcommand
+		Name string
+		// Short is a one line description of the backend
+		Short string
+		// Flags adds the flags that are specific to the backend, can be nil
+		Flags func(*pflag.FlagSet)
+		// New returns a Generator for the struct in opts. The passed in
+		// flag set contains the flags added by Flags
+		New func(Options, *pflag.FlagSet) (Generator, error)
+	}
+)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]Backend)
+)
+
+// Register makes a backend available by its name. It is meant to be called
+// from the init function of the backend package and panics if a backend with
+// the same name is already registered.
+func Register(b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if b.New == nil {
+		panic(fmt.Sprintf("generator: backend %s has no constructor", b.Name))
+	}
+	if _, exists := backends[b.Name]; exists {
+		panic(fmt.Sprintf("generator: Register called twice for backend %s", b.Name))
+	}
+	backends[b.Name] = b
+}
+
+// Lookup returns the backend registered with the name
+func Lookup(name string) (Backend, bool) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	b, ok := backends[name]
+	return b, ok
+}
+
+// Backends returns all registered backends sorted by name
+func Backends() []Backend {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	var bs []Backend
+	for _, b := range backends {
+		bs = append(bs, b)
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Name < bs[j].Name
+	})
+
+	return bs
+}
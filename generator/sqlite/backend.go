@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/pengux/cruder/generator"
+	"github.com/spf13/pflag"
+)
+
+func init() {
+	generator.Register(generator.Backend{
+		Name:  "sqlite",
+		Short: "Generates CRUD methods for SQLite (3.35 or later)",
+		Flags: func(fs *pflag.FlagSet) {
+			fs.String("table", "", "table name in the database, default to <struct>")
+			fs.Bool("noautotimestamps", false, "Take the values of CreatedAt and UpdatedAt, or the fields tagged with `cruder:\"autocreate\"` and `cruder:\"autoupdate\"`, from the struct instead of setting them to CURRENT_TIMESTAMP")
+		},
+		New: newFromOptions,
+	})
+}
+
+// newFromOptions returns a SQLite configured from the shared options and the
+// flags of the sqlite backend
+func newFromOptions(opts generator.Options, fs *pflag.FlagSet) (generator.Generator, error) {
+	gen, err := New(opts.Pkg, opts.Struct, opts.StructName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.PrimaryFields) > 1 {
+		return nil, errors.New("composite primary keys are not supported")
+	}
+	err = gen.SetOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := fs.GetString("table")
+	if err != nil {
+		return nil, err
+	}
+	if len(table) > 0 {
+		gen.TableName = table
+	}
+
+	gen.NoAutoTimestamps, err = fs.GetBool("noautotimestamps")
+	if err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	createTmpl = `
+// Create%[1]s inserts an entry into DB
+func Create%[1]s(%[3]sdb %[12]s, x %[2]s) (*%[2]s, error) {
+	var y %[2]s
+	err := db.%[4]s(
+		%[5]s` + "`" + `INSERT INTO %[6]s (%[7]s) VALUES (%[8]s)
+		RETURNING %[9]s` + "`" + `,
+		%[10]s,
+	).Scan(%[11]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateCreate generates the Create method for the struct
+func (g *SQLite) GenerateCreate() error {
+	timestampColumns, timestampValues := g.TimestampValues()
+	columns := append(g.WriteFieldDBNames(""), timestampColumns...)
+	values := append(g.PlaceholderStrings(len(g.ValueFields(g.WriteFields))), timestampValues...)
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(createTmpl,
+		suffix,
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.TableName,
+		strings.Join(columns, ", "),
+		strings.Join(values, ", "),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		strings.Join(g.WriteFieldNames("x."), ", "),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.HelperType(generator.TypeQueryRower),
+	)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	deleteTmpl = `
+// Delete%[1]s deletes an entry from DB
+func Delete%[1]s(%[2]sdb %[6]s, id interface{}) error {
+	result, err := db.%[3]s(
+		%[4]s` + "`" + `%[5]s` + "`" + `,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if r, err := result.RowsAffected(); err != nil || r == 0 {
+		if err != nil {
+			return err
+		}
+		return errors.New("sql: no rows affected")
+	}
+
+	return nil
+}
+`
+)
+
+// GenerateDelete generates the Delete method for the struct
+func (g *SQLite) GenerateDelete() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	g.AddImport("errors")
+
+	var deleteQuery string
+	if g.SoftDeleteFieldOffset != -1 {
+		deleteQuery = fmt.Sprintf("UPDATE %s SET %s = CURRENT_TIMESTAMP WHERE %s = ? AND %s IS NULL",
+			g.TableName,
+			g.FieldDBName(g.SoftDeleteFieldOffset),
+			g.FieldDBName(g.PrimaryFieldOffset()),
+			g.FieldDBName(g.SoftDeleteFieldOffset),
+		)
+	} else {
+		deleteQuery = fmt.Sprintf("DELETE FROM %s WHERE %s = ?",
+			g.TableName,
+			g.FieldDBName(g.PrimaryFieldOffset()),
+		)
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(deleteTmpl,
+		suffix,
+		g.CtxParam(),
+		g.DBMethod("Exec"),
+		g.CtxArg(),
+		deleteQuery,
+		g.HelperType(generator.TypeExecer),
+	)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	getTmpl = `
+// Get%[1]s returns a single entry from DB based on primary key
+func Get%[1]s(%[3]sdb %[11]s, id interface{}) (*%[2]s, error) {
+	var y %[2]s
+	err := db.%[4]s(
+		%[5]s` + "`" + `SELECT %[6]s FROM %[7]s WHERE %[8]s = ?%[9]s` + "`" + `,
+		id,
+	).Scan(%[10]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateGet generates the Get method for the struct
+func (g *SQLite) GenerateGet() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	var softDeleteWhere string
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.FieldDBName(g.SoftDeleteFieldOffset))
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(getTmpl,
+		suffix,
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		g.TableName,
+		g.FieldDBName(g.PrimaryFieldOffset()),
+		softDeleteWhere,
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.HelperType(generator.TypeQueryRower),
+	)
+
+	return nil
+}
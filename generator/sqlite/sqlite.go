@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+
+	"github.com/pengux/cruder/generator"
+)
+
+type (
+	// SQLite generates the CRUD methods for SQLite using database/sql.
+	SQLite struct {
+		*generator.Base
+	}
+
+	// dialect contains the SQL of SQLite needed by generator.Base
+	dialect struct{}
+)
+
+// New returns a SQLite. The fields of the struct can be configured with the
+// options of generator.NewBase, the options of the pg backend are rejected
+func New(pkg *types.Package, t *types.Struct, structModel string) (*SQLite, error) {
+	gen := &SQLite{
+		Base: generator.NewBase(pkg, t, structModel, dialect{}),
+	}
+	if err := gen.RejectTags("type", "omitempty", "version", "conflict"); err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
+
+// Generate generates CRUD code for the passed in functions and writes the
+// formatted file, including the package header, to w
+func (g *SQLite) Generate(w io.Writer, fns ...generator.Function) error {
+	if g.ExternalPkg {
+		if err := g.CheckExportedFields(); err != nil {
+			return err
+		}
+	}
+
+	for _, fn := range fns {
+		var err error
+		switch fn {
+		case generator.Create:
+			err = g.GenerateCreate()
+		case generator.Get:
+			err = g.GenerateGet()
+		case generator.List:
+			err = g.GenerateList()
+		case generator.Update:
+			err = g.GenerateUpdate()
+		case generator.Delete:
+			err = g.GenerateDelete()
+		default:
+			return fmt.Errorf("unknown function %s", fn)
+		}
+		if err != nil {
+			return fmt.Errorf("generating %s: %s", fn, err)
+		}
+	}
+
+	return g.Write(w)
+}
+
+// QuoteIdentifier returns the name unquoted, as the generated queries use
+// unquoted identifiers
+func (dialect) QuoteIdentifier(name string) string {
+	return name
+}
+
+// Placeholder returns ?, which are bound in order
+func (dialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Now returns CURRENT_TIMESTAMP
+func (dialect) Now() string {
+	return "CURRENT_TIMESTAMP"
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pengux/cruder/generator"
+)
+
+const (
+	updateTmpl = `
+// Update%[1]s updates an entry into DB
+func Update%[1]s(%[3]sdb %[13]s, x %[2]s) (*%[2]s, error) {
+	var y %[2]s
+	err := db.%[4]s(
+		%[5]s` + "`" + `UPDATE %[6]s SET %[7]s WHERE %[8]s = ?%[9]s
+		RETURNING %[10]s` + "`" + `,
+		%[11]s,
+	).Scan(%[12]s)
+
+	return &y, err
+}
+`
+)
+
+// GenerateUpdate generates the Update method for the struct
+func (g *SQLite) GenerateUpdate() error {
+	if err := g.RequireSinglePrimaryField(); err != nil {
+		return err
+	}
+
+	var setParts []string
+	for _, f := range g.WriteFieldDBNames("") {
+		setParts = append(setParts, f+" = ?")
+	}
+	setParts = append(setParts, g.TimestampSetParts()...)
+
+	var softDeleteWhere string
+	if g.SoftDeleteFieldOffset != -1 {
+		softDeleteWhere = fmt.Sprintf(" AND %s IS NULL", g.FieldDBName(g.SoftDeleteFieldOffset))
+	}
+
+	var suffix string
+	if !g.SkipSuffix {
+		suffix = g.StructName
+	}
+
+	g.Printf(updateTmpl,
+		suffix,
+		g.StructType(),
+		g.CtxParam(),
+		g.DBMethod("QueryRow"),
+		g.CtxArg(),
+		g.TableName,
+		strings.Join(setParts, ", "),
+		g.FieldDBName(g.PrimaryFieldOffset()),
+		softDeleteWhere,
+		strings.Join(g.ReadFieldDBNames(""), ", "),
+		strings.Join(append(g.WriteFieldNames("x."), "x."+g.Struct.Field(g.PrimaryFieldOffset()).Name()), ", "),
+		strings.Join(g.ReadFieldNames("&y."), ", "),
+		g.HelperType(generator.TypeQueryRower),
+	)
+
+	return nil
+}
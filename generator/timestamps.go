@@ -0,0 +1,71 @@
+package generator
+
+// InsertTimestampFields returns the fields set to the current time when
+// inserting an entry, which are both the created at and the updated at fields
+func (b *Base) InsertTimestampFields() map[int]string {
+	fields := make(map[int]string)
+	if b.NoAutoTimestamps {
+		return fields
+	}
+
+	for k, f := range b.CreatedAtFields {
+		fields[k] = f
+	}
+	for k, f := range b.UpdatedAtFields {
+		fields[k] = f
+	}
+
+	return fields
+}
+
+// UpdateTimestampFields returns the fields set to the current time when
+// updating an entry
+func (b *Base) UpdateTimestampFields() map[int]string {
+	if b.NoAutoTimestamps {
+		return map[int]string{}
+	}
+
+	return b.UpdatedAtFields
+}
+
+// ValueFields returns the fields without the timestamp fields set by the
+// database, as their values are never taken from the struct
+func (b *Base) ValueFields(fields map[int]string) map[int]string {
+	timestampFields := b.InsertTimestampFields()
+
+	r := make(map[int]string, len(fields))
+	for k, f := range fields {
+		if _, ok := timestampFields[k]; ok {
+			continue
+		}
+		r[k] = f
+	}
+
+	return r
+}
+
+// TimestampValues returns the DB names of the timestamp fields and the current
+// time for each of them, to append to the columns and values of an INSERT
+// statement. The timestamp fields are set by the database so the creation and
+// update times don't depend on the clock of the client, and an update with a
+// zero CreatedAt doesn't wipe the creation time
+func (b *Base) TimestampValues() ([]string, []string) {
+	columns := b.FieldDBNames(b.InsertTimestampFields(), "")
+	values := make([]string, len(columns))
+	for i := range values {
+		values[i] = b.Dialect.Now()
+	}
+
+	return columns, values
+}
+
+// TimestampSetParts returns the assignments of the current time to the updated
+// at fields, to append to the SET clause of an UPDATE statement
+func (b *Base) TimestampSetParts() []string {
+	var setParts []string
+	for _, f := range b.FieldDBNames(b.UpdateTimestampFields(), "") {
+		setParts = append(setParts, f+" = "+b.Dialect.Now())
+	}
+
+	return setParts
+}
@@ -0,0 +1,158 @@
+package generator
+
+import "fmt"
+
+// TypesFileName is the name of the file declaring the helper types which are
+// shared by the generated files of a package
+const TypesFileName = "cruder_types.crud.go"
+
+// noContextSuffix is added to the names of the helper types used by code
+// generated without context.Context
+const noContextSuffix = "NoContext"
+
+// Names of the helper types which are used as database handle parameters of
+// the generated functions
+const (
+	TypeExecer         = "cruderExecer"
+	TypeQueryer        = "cruderQueryer"
+	TypeQueryRower     = "cruderQueryRower"
+	TypePreparer       = "cruderPreparer"
+	TypeExecQueryRower = "cruderExecQueryRower"
+	TypeDB             = "cruderDB"
+)
+
+// contextTypes are the helper types which have a variant without
+// context.Context
+var contextTypes = map[string]bool{
+	TypeExecer:         true,
+	TypeQueryer:        true,
+	TypeQueryRower:     true,
+	TypePreparer:       true,
+	TypeExecQueryRower: true,
+	TypeDB:             true,
+}
+
+// typesHeader is the header of TypesFileName. Unlike Header, it doesn't
+// contain the command line, as the file is written by every command generating
+// into the package
+const typesHeader = "// Package %[1]s contains CRUD methods that are generated by `cruder`\n// Code generated by cruder; DO NOT EDIT\n"
+
+// typesTmpl contains all the helper types, so the file is the same whichever
+// backends and functions are generated in the package
+const typesTmpl = `package %[1]s
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type cruderExecer interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+}
+
+type cruderQueryer interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}
+
+type cruderQueryRower interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+type cruderPreparer interface {
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+}
+
+type cruderExecQueryRower interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+type cruderDB interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+}
+
+type cruderExecer%[2]s interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+}
+
+type cruderQueryer%[2]s interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+}
+
+type cruderQueryRower%[2]s interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}
+
+type cruderPreparer%[2]s interface {
+	Prepare(string) (*sql.Stmt, error)
+}
+
+type cruderExecQueryRower%[2]s interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+	QueryRow(string, ...interface{}) *sql.Row
+}
+
+type cruderDB%[2]s interface {
+	Exec(string, ...interface{}) (sql.Result, error)
+	Query(string, ...interface{}) (*sql.Rows, error)
+	QueryRow(string, ...interface{}) *sql.Row
+	Prepare(string) (*sql.Stmt, error)
+}
+
+type cruderSQLFilter interface {
+	Where() (string, []interface{})
+}
+
+type cruderSQLSorter interface {
+	OrderBy() string
+}
+
+// cruderJSONB stores the value pointed to by v as JSON
+type cruderJSONB struct {
+	v interface{}
+}
+
+// Value implements driver.Valuer. The JSON is returned as a string since lib/pq
+// sends []byte as bytea
+func (j cruderJSONB) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.v)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner
+func (j cruderJSONB) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(src, j.v)
+	case string:
+		return json.Unmarshal([]byte(src), j.v)
+	}
+
+	return fmt.Errorf("cruderJSONB: unsupported type %%T", src)
+}
+`
+
+// Types returns the content of the file declaring the helper types in the
+// package pkgName, which should be written to TypesFileName next to the
+// generated files
+func Types(pkgName string) []byte {
+	return []byte(fmt.Sprintf(typesHeader+typesTmpl, pkgName, noContextSuffix))
+}
+
+// HelperType returns the name of the helper type in the variant for code
+// generated with or without context.Context
+func HelperType(name string, noContext bool) string {
+	if noContext && contextTypes[name] {
+		return name + noContextSuffix
+	}
+
+	return name
+}
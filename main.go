@@ -1,6 +1,13 @@
 package main
 
-import "github.com/pengux/cruder/cmd"
+import (
+	"github.com/pengux/cruder/cmd"
+
+	// Backends register themselves as subcommands
+	_ "github.com/pengux/cruder/generator/mysql"
+	_ "github.com/pengux/cruder/generator/pg"
+	_ "github.com/pengux/cruder/generator/sqlite"
+)
 
 func main() {
 	cmd.Execute()
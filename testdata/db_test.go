@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// The driver adds the NOW() function used by the pg and mysql backends to
+// SQLite, in the text format SQLite uses for CURRENT_TIMESTAMP
+func init() {
+	sql.Register("sqlite3_now", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("now", func() string {
+				return time.Now().UTC().Format("2006-01-02 15:04:05")
+			}, false)
+		},
+	})
+}
+
+// openDB returns an in-memory database with the tables of the schema
+func openDB(t *testing.T, schema string) *sql.DB {
+	db, err := sql.Open("sqlite3_now", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Every connection opens another in-memory database
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
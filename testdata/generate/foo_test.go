@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Each struct gets the functions listed for it in cruder.yaml. Bar is generated
+// without context.Context, both use the helper types of cruder_types.crud.go
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		CREATE TABLE bar (id INTEGER PRIMARY KEY, count INTEGER NOT NULL)`)
+
+	foo, err := CreateFoo(ctx, db, Foo{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetFoo(ctx, db, foo.ID); err != nil || *got != *foo {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, foo)
+	}
+
+	if _, err := CreateBar(db, Bar{Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	bars, err := ListBars(db, 0, 0, nil, nil)
+	if err != nil || len(bars) != 1 || bars[0].Count != 1 {
+		t.Fatalf("ListBars: got %v, %v, want the created entry", bars, err)
+	}
+}
+
+// The generated files name the command line that generated them, except the
+// helper types file which doesn't depend on which of the structs was generated
+// last
+func TestHeaders(t *testing.T) {
+	for file, want := range map[string]string{
+		"foo_crud.go":          `// Code generated by "cruder generate -c `,
+		"cruder_types.crud.go": "// Code generated by cruder; DO NOT EDIT\n",
+	} {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header := strings.SplitN(string(b), "\n", 2)[1]; !strings.HasPrefix(header, want) {
+			t.Fatalf("header of %s: got %q, want %q", file, header, want)
+		}
+	}
+}
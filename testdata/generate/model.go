@@ -0,0 +1,14 @@
+package main
+
+// Foo and Bar are generated with the settings of cruder.yaml
+type (
+	Foo struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	Bar struct {
+		ID    int64 `db:"id"`
+		Count int64 `db:"count"`
+	}
+)
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// Round trip through the functions of the mysql backend, which runs against
+// SQLite as it also accepts backticks and ? as placeholders
+func TestCRUD(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		deleted_at DATETIME
+	)`)
+
+	a, err := CreateFoo(ctx, db, Foo{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == 0 || a.Name != "a" {
+		t.Fatalf("CreateFoo: got %v, want the auto increment ID set", a)
+	}
+
+	a.Name = "b"
+	updated, err := UpdateFoo(ctx, db, *a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != a.ID || updated.Name != "b" {
+		t.Fatalf("UpdateFoo: got %v, want %v", updated, a)
+	}
+
+	got, err := GetFoo(ctx, db, a.ID)
+	if err != nil || *got != *updated {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, updated)
+	}
+	foos, err := ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0] != *updated {
+		t.Fatalf("ListFoos: got %v, %v, want [%v]", foos, err, updated)
+	}
+
+	if err := DeleteFoo(ctx, db, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetFoo(ctx, db, a.ID); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	foos, err = ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 0 {
+		t.Fatalf("ListFoos after delete: got %v, %v, want none", foos, err)
+	}
+
+	// The entry is only soft deleted
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM foo WHERE deleted_at IS NOT NULL").Scan(&n); err != nil || n != 1 {
+		t.Fatalf("counting soft deleted entries: got %d, %v, want 1", n, err)
+	}
+}
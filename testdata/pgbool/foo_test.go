@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestBoolSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, is_deleted BOOLEAN NOT NULL DEFAULT FALSE);
+		INSERT INTO foo (id, name, is_deleted) VALUES (1, 'a', FALSE), (2, 'b', TRUE)`)
+
+	if err := DeleteFoo(ctx, db, 1); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	if _, err := GetFoo(ctx, db, 1); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo after delete: got %v, want sql.ErrNoRows", err)
+	}
+	if n, err := CountFoo(ctx, db, nil); err != nil || n != 0 {
+		t.Fatalf("CountFoo: got %d, %v, want 0", n, err)
+	}
+
+	if err := RestoreFoo(ctx, db, 2); err != nil {
+		t.Fatalf("RestoreFoo: %s", err)
+	}
+	foos, err := ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0].Name != "b" {
+		t.Fatalf("ListFoos after restore: got %v, %v, want b", foos, err)
+	}
+}
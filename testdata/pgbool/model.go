@@ -0,0 +1,8 @@
+package main
+
+// Foo is soft deleted by setting IsDeleted to true
+type Foo struct {
+	ID        int64  `db:"id"`
+	Name      string `db:"name"`
+	IsDeleted bool   `db:"is_deleted"`
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// Every part of the composite key is used to look up an entry
+func TestCompositeKey(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foo (tenant_id INTEGER NOT NULL, id INTEGER NOT NULL, name TEXT NOT NULL, PRIMARY KEY (tenant_id, id))")
+
+	// The fields of the composite key are inserted as they are not generated
+	// by the database
+	a, err := CreateFoo(ctx, db, Foo{TenantID: 1, ID: 1, Name: "a"})
+	if err != nil || *a != (Foo{TenantID: 1, ID: 1, Name: "a"}) {
+		t.Fatalf("CreateFoo: got %v, %v", a, err)
+	}
+	foos, err := CreateManyFoo(ctx, db, []Foo{{TenantID: 2, ID: 1, Name: "b"}, {TenantID: 2, ID: 2, Name: "c"}})
+	if err != nil || len(foos) != 2 || foos[1] != (Foo{TenantID: 2, ID: 2, Name: "c"}) {
+		t.Fatalf("CreateManyFoo: got %v, %v", foos, err)
+	}
+	if err := DeleteFoo(ctx, db, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	foo, err := GetFoo(ctx, db, 2, 1)
+	if err != nil || foo.Name != "b" {
+		t.Fatalf("GetFoo(2, 1): got %v, %v, want b", foo, err)
+	}
+
+	foo, err = UpdateFoo(ctx, db, Foo{TenantID: 1, ID: 1, Name: "c"})
+	if err != nil || foo.Name != "c" {
+		t.Fatalf("UpdateFoo: got %v, %v, want c", foo, err)
+	}
+	if foo, err = GetFoo(ctx, db, 2, 1); err != nil || foo.Name != "b" {
+		t.Fatalf("GetFoo(2, 1) after update: got %v, %v, want b", foo, err)
+	}
+
+	if err := DeleteFoo(ctx, db, 1, 1); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	if _, err := GetFoo(ctx, db, 1, 1); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo(1, 1) after delete: got %v, want sql.ErrNoRows", err)
+	}
+
+	for _, tt := range []struct {
+		tenantID, id int64
+		want         bool
+	}{
+		{1, 1, false},
+		{2, 1, true},
+		{2, 2, false},
+	} {
+		exists, err := ExistsFoo(ctx, db, tt.tenantID, tt.id)
+		if err != nil || exists != tt.want {
+			t.Fatalf("ExistsFoo(%d, %d): got %t, %v, want %t", tt.tenantID, tt.id, exists, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+// Foo has a composite primary key
+type Foo struct {
+	TenantID int64  `db:"tenant_id" cruder:"pk"`
+	ID       int64  `db:"id" cruder:"pk"`
+	Name     string `db:"name"`
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// Count and Exists leave out the soft deleted entries, like List
+func TestCountExists(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, deleted_at DATETIME);
+		INSERT INTO foo (id, name, deleted_at) VALUES (1, 'a', NULL), (2, 'b', NULL), (3, 'b', NULL), (4, 'b', NOW())`)
+
+	for _, tt := range []struct {
+		filter cruderSQLFilter
+		want   int64
+	}{
+		{nil, 3},
+		{nameFilter("b"), 2},
+		{nameFilter("c"), 0},
+	} {
+		n, err := CountFoo(ctx, db, tt.filter)
+		if err != nil || n != tt.want {
+			t.Fatalf("CountFoo(%v): got %d, %v, want %d", tt.filter, n, err, tt.want)
+		}
+		foos, err := ListFoos(ctx, db, 0, 0, tt.filter, nil)
+		if err != nil || int64(len(foos)) != tt.want {
+			t.Fatalf("ListFoos(%v): got %v, %v, want %d entries", tt.filter, foos, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		id   int64
+		want bool
+	}{
+		{1, true},
+		{4, false},
+		{5, false},
+	} {
+		exists, err := ExistsFoo(ctx, db, tt.id)
+		if err != nil || exists != tt.want {
+			t.Fatalf("ExistsFoo(%d): got %t, %v, want %t", tt.id, exists, err, tt.want)
+		}
+	}
+}
+
+type nameFilter string
+
+func (f nameFilter) Where() (string, []interface{}) {
+	return "name = $1", []interface{}{string(f)}
+}
@@ -0,0 +1,10 @@
+package main
+
+import "time"
+
+// Foo is soft deleted through DeletedAt
+type Foo struct {
+	ID        int64      `db:"id"`
+	Name      string     `db:"name"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
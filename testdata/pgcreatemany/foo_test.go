@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// The entries are returned in the order they are passed in, with the ID set
+// by the database
+func TestCreateMany(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER NOT NULL)")
+
+	foos, err := CreateManyFoo(ctx, db, []Foo{{Name: "a", Rank: 1}, {Name: "b", Rank: 2}, {Name: "c", Rank: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(foos) != 3 {
+		t.Fatalf("CreateManyFoo: got %v, want 3 entries", foos)
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if foos[i].ID == 0 || foos[i].Name != want || foos[i].Rank != i+1 {
+			t.Fatalf("CreateManyFoo: got %v at %d, want %s with an ID", foos[i], i, want)
+		}
+	}
+
+	foos, err = CreateManyFoo(ctx, db, nil)
+	if err != nil || len(foos) != 0 {
+		t.Fatalf("CreateManyFoo without entries: got %v, %v, want none", foos, err)
+	}
+
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM foo").Scan(&n); err != nil || n != 3 {
+		t.Fatalf("counting the entries: got %d, %v, want 3", n, err)
+	}
+}
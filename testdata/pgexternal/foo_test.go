@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pengux/cruder/testdata/pgexternal/models"
+)
+
+// The functions are generated into this package and use models.Foo
+func TestExternalPackage(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foo (id INTEGER PRIMARY KEY, label TEXT NOT NULL, created_at DATETIME NOT NULL)")
+
+	foo, err := CreateFoo(ctx, db, models.Foo{Label: "a"})
+	if err != nil || foo.Label != "a" || foo.CreatedAt.IsZero() {
+		t.Fatalf("CreateFoo: got %v, %v", foo, err)
+	}
+
+	got, err := GetFoo(ctx, db, foo.ID)
+	if err != nil || *got != *foo {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, foo)
+	}
+
+	foos, err := ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0] != *foo {
+		t.Fatalf("ListFoos: got %v, %v, want [%v]", foos, err, foo)
+	}
+}
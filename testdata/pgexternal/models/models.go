@@ -0,0 +1,15 @@
+// Package models declares Foo for the code generated into another package
+package models
+
+import "time"
+
+// Label is declared in the package of Foo, so it is qualified in the generated
+// code
+type Label string
+
+// Foo is an entry with fields of types from its own and other packages
+type Foo struct {
+	ID        int64     `db:"id"`
+	Label     Label     `db:"label"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// The filters render numbered placeholders that match their arguments, also
+// when combined
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER, deleted BOOLEAN NOT NULL DEFAULT FALSE);
+		INSERT INTO foo (id, name, rank) VALUES (1, 'a', 1), (2, 'b', 2), (3, 'c', 3), (4, 'ab', NULL)`)
+
+	for _, tt := range []struct {
+		name   string
+		filter FooFilter
+		want   []string
+	}{
+		{"zero value", FooFilter{}, []string{"a", "b", "c", "ab"}},
+		{"Eq", FooFilter{}.NameEq("b"), []string{"b"}},
+		{"Like", FooFilter{}.NameLike("a%"), []string{"a", "ab"}},
+		{"In", FooFilter{}.NameIn("a", "c", "d"), []string{"a", "c"}},
+		{"empty In", FooFilter{}.NameIn(), []string{}},
+		{"Between", FooFilter{}.RankBetween(2, 3), []string{"b", "c"}},
+		{"IsNull", FooFilter{}.RankIsNull(), []string{"ab"}},
+		{"chained", FooFilter{}.RankGte(2).NameNe("c"), []string{"b"}},
+		{"Not", FooFilter{}.RankLt(2).Not(), []string{"b", "c"}},
+		{"And", FooFilter{}.NameLike("a%").And(FooFilter{}.RankIsNotNull(), FooFilter{}), []string{"a"}},
+	} {
+		foos, err := ListFoos(ctx, db, 0, 0, tt.filter, nil)
+		check(t, "ListFoos with "+tt.name, names(foos), err, tt.want)
+
+		n, err := CountFoo(ctx, db, tt.filter)
+		check(t, "CountFoo with "+tt.name, n, err, int64(len(tt.want)))
+	}
+}
+
+// A filter using OR doesn't bypass the soft deletion and the keyset condition
+func TestFilterOr(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER, deleted BOOLEAN NOT NULL DEFAULT FALSE);
+		INSERT INTO foo (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+	if err := DeleteFoo(ctx, db, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	aOrB := FooFilter{}.NameEq("a").Or(FooFilter{}.NameEq("b"))
+	aOrC := FooFilter{}.NameEq("a").Or(FooFilter{}.NameEq("c"))
+
+	foos, err := ListFoos(ctx, db, 0, 0, aOrB, nil)
+	check(t, "ListFoos", names(foos), err, []string{"a"})
+
+	n, err := CountFoo(ctx, db, aOrB)
+	check(t, "CountFoo", n, err, int64(1))
+
+	foos, err = ListDeletedFoos(ctx, db, 0, 0, aOrC, nil)
+	check(t, "ListDeletedFoos", names(foos), err, []string{})
+
+	foos, cursor, err := ListFooAfter(ctx, db, "", 1, aOrC)
+	check(t, "ListFooAfter first page", names(foos), err, []string{"a"})
+	foos, _, err = ListFooAfter(ctx, db, cursor, 1, aOrC)
+	check(t, "ListFooAfter second page", names(foos), err, []string{"c"})
+}
+
+func names(foos []Foo) []string {
+	r := []string{}
+	for _, f := range foos {
+		r = append(r, f.Name)
+	}
+
+	return r
+}
+
+func check(t *testing.T, name string, got interface{}, err error, want interface{}) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+// Foo is filtered with FooFilter, and soft deleted with the bool strategy as
+// SQLite has no NOW()
+type Foo struct {
+	ID      int64  `db:"id"`
+	Name    string `db:"name"`
+	Rank    *int   `db:"rank"`
+	Deleted bool   `db:"deleted" cruder:"softdelete"`
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+// anyQueryer rewrites the = ANY($1) of GetMany, which SQLite doesn't support,
+// to a lookup in the array literal passed by pq.Array
+type anyQueryer struct {
+	db *sql.DB
+}
+
+func (q anyQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query = strings.Replace(query, "= ANY($1)", "IN (SELECT value FROM json_each('[' || trim($1, '{}') || ']'))", 1)
+	return q.db.QueryContext(ctx, query, args...)
+}
+
+// GetMany returns the entries by ID in a map, leaving out missing and soft
+// deleted entries
+func TestGetMany(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, deleted_at DATETIME);
+		INSERT INTO foo (id, name, deleted_at) VALUES (1, 'a', NULL), (2, 'b', NULL), (3, 'c', NOW())`)
+
+	foos, err := GetManyFoo(ctx, anyQueryer{db}, []int64{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(foos) != 2 || foos[1].Name != "a" || foos[2].Name != "b" {
+		t.Fatalf("GetManyFoo: got %v, want the entries 1 and 2", foos)
+	}
+
+	foos, err = GetManyFoo(ctx, anyQueryer{db}, nil)
+	if err != nil || len(foos) != 0 {
+		t.Fatalf("GetManyFoo without IDs: got %v, %v, want none", foos, err)
+	}
+}
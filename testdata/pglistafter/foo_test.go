@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// Paging through all entries with the returned cursors yields each entry once,
+// in the order of the cursor field and the primary key
+func TestListAfter(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER NOT NULL);
+		INSERT INTO foo (id, name, rank) VALUES (1, 'a', 2), (2, 'b', 1), (3, 'c', 2), (4, 'd', 1), (5, 'e', 3)`)
+
+	var names []string
+	var cursor string
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("ListFooAfter: no empty cursor after %d pages", pages)
+		}
+
+		foos, next, err := ListFooAfter(ctx, db, cursor, 2, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, foo := range foos {
+			names = append(names, foo.Name)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if got, want := fmt.Sprint(names), "[b d a c e]"; got != want {
+		t.Fatalf("ListFooAfter: got %s, want %s", got, want)
+	}
+
+	if _, _, err := ListFooAfter(ctx, db, "invalid", 2, nil); err == nil {
+		t.Fatal("ListFooAfter with an invalid cursor: got no error")
+	}
+}
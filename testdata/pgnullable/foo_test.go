@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// The soft delete field gets the strategy of the type it wraps, so entries
+// with FALSE or another status than deleted are not deleted, and neither are
+// entries with NULL
+func TestNullableSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, is_deleted BOOLEAN, removed BOOLEAN, status TEXT, state TEXT);
+		INSERT INTO foo (id, name) VALUES (1, 'a');
+		INSERT INTO foo (id, name, is_deleted, removed, status, state) VALUES (2, 'b', FALSE, FALSE, 'active', 'active'), (3, 'c', NULL, NULL, NULL, NULL)`)
+
+	if n, err := CountFoo(ctx, db, nil); err != nil || n != 3 {
+		t.Fatalf("CountFoo: got %d, %v, want 3", n, err)
+	}
+
+	if err := DeleteFoo(ctx, db, 1); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	if _, err := GetFoo(ctx, db, 1); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo after delete: got %v, want sql.ErrNoRows", err)
+	}
+
+	foos, err := ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 2 || foos[0].ID != 2 || foos[1].ID != 3 {
+		t.Fatalf("ListFoos: got %v, %v, want the entries 2 and 3", foos, err)
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "database/sql"
+
+// Foo has a nullable field for each type of soft delete field wrapping a bool
+// or a string, the tests pass one of them as --softdeletefield
+type Foo struct {
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	IsDeleted *bool          `db:"is_deleted"`
+	Removed   sql.NullBool   `db:"removed"`
+	Status    *string        `db:"status"`
+	State     sql.NullString `db:"state"`
+}
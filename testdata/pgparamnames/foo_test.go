@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// The parameters of the primary fields don't clash with the variables of the
+// generated functions
+func TestParamNames(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foo (a INTEGER NOT NULL, b INTEGER NOT NULL, name TEXT NOT NULL, PRIMARY KEY (a, b))")
+
+	if _, err := CreateFoo(ctx, db, Foo{Result: 1, Exists: 2, Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := ExistsFoo(ctx, db, 1, 2); err != nil || !exists {
+		t.Fatalf("ExistsFoo: got %t, %v, want true", exists, err)
+	}
+
+	if err := DeleteFoo(ctx, db, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := ExistsFoo(ctx, db, 1, 2); err != nil || exists {
+		t.Fatalf("ExistsFoo after delete: got %t, %v, want false", exists, err)
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+// Foo has primary fields named like the variables of the generated functions
+type Foo struct {
+	Result int64  `db:"a" cruder:"pk"`
+	Exists int64  `db:"b" cruder:"pk"`
+	Name   string `db:"name"`
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// The repository methods take the typed fields of the composite primary key
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foos (tenant_id INTEGER NOT NULL, id INTEGER NOT NULL, name TEXT NOT NULL, status TEXT NOT NULL DEFAULT 'active', PRIMARY KEY (tenant_id, id))")
+
+	repo := NewFooRepository(db)
+	repo.Table = "foos"
+	var store FooStore = repo
+
+	a, err := store.Create(ctx, Foo{TenantID: 1, ID: 1, Name: "a"})
+	if err != nil || *a != (Foo{TenantID: 1, ID: 1, Name: "a"}) {
+		t.Fatalf("Create: got %v, %v", a, err)
+	}
+	if _, err := store.Create(ctx, Foo{TenantID: 2, ID: 1, Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(ctx, 3, a.ID); err != sql.ErrNoRows {
+		t.Fatalf("Get with another tenant: got %v, want sql.ErrNoRows", err)
+	}
+	got, err := store.Get(ctx, 1, a.ID)
+	if err != nil || *got != *a {
+		t.Fatalf("Get: got %v, %v, want %v", got, err, a)
+	}
+
+	a.Name = "aa"
+	updated, err := store.Update(ctx, *a)
+	if err != nil || *updated != *a {
+		t.Fatalf("Update: got %v, %v, want %v", updated, err, a)
+	}
+
+	if err := store.Delete(ctx, 1, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := store.Exists(ctx, 1, a.ID); err != nil || ok {
+		t.Fatalf("Exists on a deleted entry: got %t, %v, want false", ok, err)
+	}
+	foos, err := store.List(ctx, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0].Name != "b" {
+		t.Fatalf("List: got %v, %v, want the entry b", foos, err)
+	}
+
+	var status string
+	if err := db.QueryRow("SELECT status FROM foos WHERE tenant_id = 1 AND id = ?", a.ID).Scan(&status); err != nil || status != "deleted" {
+		t.Fatalf("status of the deleted entry: got %q, %v, want deleted", status, err)
+	}
+}
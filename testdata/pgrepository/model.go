@@ -0,0 +1,10 @@
+package main
+
+// Foo has a composite primary key and is soft deleted with the status
+// strategy
+type Foo struct {
+	TenantID int64  `db:"tenant_id" cruder:"pk"`
+	ID       int64  `db:"id" cruder:"pk"`
+	Name     string `db:"name"`
+	Status   string `db:"status" cruder:"softdelete"`
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, deleted_at DATETIME);
+		INSERT INTO foo (id, name, deleted_at) VALUES (1, 'a', NULL), (2, 'b', '2020-01-01 00:00:00'), (3, 'c', '2020-02-01 00:00:00')`)
+
+	if err := DeleteFoo(ctx, db, 1); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	deleted, err := ListDeletedFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(deleted) != 3 {
+		t.Fatalf("ListDeletedFoos: got %v, %v, want 3 entries", deleted, err)
+	}
+
+	if err := RestoreFoo(ctx, db, 1); err != nil {
+		t.Fatalf("RestoreFoo: %s", err)
+	}
+	if err := RestoreFoo(ctx, db, 1); err == nil {
+		t.Fatal("RestoreFoo of an entry which isn't deleted: got no error")
+	}
+	foo, err := GetFoo(ctx, db, 1)
+	if err != nil || foo.DeletedAt != nil {
+		t.Fatalf("GetFoo after restore: got %v, %v, want an entry which isn't deleted", foo, err)
+	}
+
+	n, err := PurgeFoo(ctx, db, time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeFoo: got %d, %v, want 1", n, err)
+	}
+
+	if err := HardDeleteFoo(ctx, db, 3); err != nil {
+		t.Fatalf("HardDeleteFoo of a soft deleted entry: %s", err)
+	}
+	if err := HardDeleteFoo(ctx, db, 1); err != nil {
+		t.Fatalf("HardDeleteFoo: %s", err)
+	}
+	if deleted, err := ListDeletedFoos(ctx, db, 0, 0, nil, nil); err != nil || len(deleted) != 0 {
+		t.Fatalf("ListDeletedFoos after purge: got %v, %v, want no entries", deleted, err)
+	}
+	if foos, err := ListFoos(ctx, db, 0, 0, nil, nil); err != nil || len(foos) != 0 {
+		t.Fatalf("ListFoos after hard delete: got %v, %v, want no entries", foos, err)
+	}
+}
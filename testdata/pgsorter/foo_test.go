@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// The parsed sort orders are used by List and unknown columns are rejected
+func TestSorter(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER);
+		INSERT INTO foo (id, name, rank) VALUES (1, 'a', 2), (2, 'b', NULL), (3, 'c', 1), (4, 'a', 1)`)
+
+	for _, tt := range []struct {
+		sort string
+		want string
+	}{
+		{"name,-id", "[4 1 2 3]"},
+		{"+rank:nullsfirst,id", "[2 3 4 1]"},
+		{"-rank:nullslast, name", "[1 4 3 2]"},
+	} {
+		order, err := ParseFooSort(tt.sort)
+		if err != nil {
+			t.Fatalf("ParseFooSort(%q): %s", tt.sort, err)
+		}
+		foos, err := ListFoos(ctx, db, 0, 0, nil, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, foo := range foos {
+			ids = append(ids, foo.ID)
+		}
+		if got := fmt.Sprint(ids); got != tt.want {
+			t.Fatalf("ListFoos sorted by %q: got %s, want %s", tt.sort, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"secret", "name;DROP TABLE foo", "name:nullsmiddle"} {
+		if _, err := ParseFooSort(s); err == nil {
+			t.Fatalf("ParseFooSort(%q): got no error", s)
+		}
+	}
+
+	if got, want := (FooSort{{Column: "secret"}, {Column: FooSortByName, Desc: true}}).OrderBy(), "name DESC"; got != want {
+		t.Fatalf("OrderBy with an invalid column: got %q, want %q", got, want)
+	}
+}
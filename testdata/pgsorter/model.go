@@ -0,0 +1,8 @@
+package main
+
+// Foo is sorted with FooSort
+type Foo struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	Rank *int   `db:"rank"`
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestStatusSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, status TEXT NOT NULL);
+		INSERT INTO foo (id, name, status) VALUES (1, 'a', 'active'), (2, 'b', 'draft'), (3, 'c', 'archived')`)
+
+	if n, err := CountFoo(ctx, db, nil); err != nil || n != 2 {
+		t.Fatalf("CountFoo: got %d, %v, want 2", n, err)
+	}
+
+	if err := DeleteFoo(ctx, db, 1); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	if _, err := GetFoo(ctx, db, 1); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo after delete: got %v, want sql.ErrNoRows", err)
+	}
+	var status string
+	if err := db.QueryRow(`SELECT status FROM foo WHERE id = 1`).Scan(&status); err != nil || status != "archived" {
+		t.Fatalf("status after delete: got %q, %v, want archived", status, err)
+	}
+
+	foos, err := ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0].ID != 2 {
+		t.Fatalf("ListFoos: got %v, %v, want the entry 2", foos, err)
+	}
+
+}
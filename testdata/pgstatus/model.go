@@ -0,0 +1,8 @@
+package main
+
+// Foo is soft deleted by setting Status to archived
+type Foo struct {
+	ID     int64  `db:"id"`
+	Name   string `db:"name"`
+	Status string `db:"status"`
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// The ignored CreatedAt has no column. The zero value of Rank, which would be
+// inserted as DEFAULT, is not tested as SQLite doesn't support DEFAULT in VALUES
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, "CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, rank INTEGER NOT NULL DEFAULT 5, labels TEXT NOT NULL)")
+
+	labels := map[string]string{"color": "red"}
+	a, err := CreateFoo(ctx, db, Foo{ID: 1, Name: "a", Rank: 3, Labels: labels})
+	if err != nil || a.Rank != 3 || !reflect.DeepEqual(a.Labels, labels) {
+		t.Fatalf("CreateFoo: got %v, %v, want the rank and the labels", a, err)
+	}
+
+	b, err := CreateFoo(ctx, db, Foo{ID: 2, Name: "b", Rank: 1})
+	if err != nil || b.Labels != nil {
+		t.Fatalf("CreateFoo without labels: got %v, %v", b, err)
+	}
+
+	var stored string
+	if err := db.QueryRow("SELECT labels FROM foo WHERE id = 1").Scan(&stored); err != nil || stored != `{"color":"red"}` {
+		t.Fatalf("stored labels: got %q, %v", stored, err)
+	}
+
+	got, err := GetFoo(ctx, db, 1)
+	if err != nil || !reflect.DeepEqual(got, a) {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, a)
+	}
+
+	foos, err := ListFoos(ctx, db, 0, 0, FooFilter{}.LabelsEq(labels), nil)
+	if err != nil || len(foos) != 1 || foos[0].Name != "a" {
+		t.Fatalf("ListFoos with LabelsEq: got %v, %v, want the entry a", foos, err)
+	}
+}
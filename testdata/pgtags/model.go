@@ -0,0 +1,13 @@
+package main
+
+import "time"
+
+// Foo has a field inserted as DEFAULT when zero, a field stored as JSON and an
+// ignored timestamp field
+type Foo struct {
+	ID        int64             `db:"id"`
+	Name      string            `db:"name"`
+	Rank      int64             `db:"rank" cruder:"omitempty"`
+	Labels    map[string]string `db:"labels" cruder:"type=jsonb"`
+	CreatedAt time.Time         `db:"created_at" cruder:"-"`
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTimestamps(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL);
+		INSERT INTO foo (id, name, created_at, updated_at) VALUES (1, 'a', '2020-01-01 00:00:00', '2020-01-01 00:00:00')`)
+
+	foo, err := CreateFoo(ctx, db, Foo{ID: 2, Name: "b"})
+	if err != nil || foo.CreatedAt.IsZero() || !foo.UpdatedAt.Equal(foo.CreatedAt) {
+		t.Fatalf("CreateFoo: got %v, %v, want both timestamps set to the current time", foo, err)
+	}
+
+	// The zero CreatedAt of the struct doesn't overwrite the creation time
+	foo, err = UpdateFoo(ctx, db, Foo{ID: 1, Name: "c"})
+	if err != nil {
+		t.Fatalf("UpdateFoo: %s", err)
+	}
+	if foo.CreatedAt.Year() != 2020 || foo.UpdatedAt.Year() == 2020 {
+		t.Fatalf("UpdateFoo: got %v, want only UpdatedAt set to the current time", foo)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "time"
+
+// Foo has the timestamp fields set by the database
+type Foo struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
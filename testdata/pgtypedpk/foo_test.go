@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// The primary key parameters have the type of the primary field
+var (
+	_ func(context.Context, cruderQueryRower, string) (*Foo, error) = GetFoo
+	_ func(context.Context, cruderExecer, string) error             = DeleteFoo
+)
+
+func TestTypedPrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id TEXT PRIMARY KEY, name TEXT NOT NULL);
+		INSERT INTO foo (id, name) VALUES ('a', 'x')`)
+
+	foo, err := GetFoo(ctx, db, "a")
+	if err != nil || foo.Name != "x" {
+		t.Fatalf("GetFoo(a): got %v, %v, want x", foo, err)
+	}
+
+	if err := DeleteFoo(ctx, db, "a"); err != nil {
+		t.Fatalf("DeleteFoo: %s", err)
+	}
+	if _, err := GetFoo(ctx, db, "a"); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo(a) after delete: got %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+// Foo has a primary key that is not an integer
+type Foo struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
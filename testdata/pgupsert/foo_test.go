@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// Upserts update the entry with the same conflict field, unless it is soft
+// deleted, in which case it stays deleted
+func TestUpsert(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (
+		id INTEGER PRIMARY KEY,
+		code TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		deleted_at DATETIME
+	)`)
+
+	a, err := UpsertFoo(ctx, db, Foo{Code: "a", Name: "a"})
+	if err != nil || a.ID == 0 || a.Name != "a" {
+		t.Fatalf("UpsertFoo inserting: got %v, %v", a, err)
+	}
+	b, err := UpsertFoo(ctx, db, Foo{Code: "a", Name: "b"})
+	if err != nil || b.ID != a.ID || b.Name != "b" {
+		t.Fatalf("UpsertFoo updating: got %v, %v, want ID %d", b, err, a.ID)
+	}
+
+	if err := DeleteFoo(ctx, db, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpsertFoo(ctx, db, Foo{Code: "a", Name: "c"}); err != sql.ErrNoRows {
+		t.Fatalf("UpsertFoo on a soft deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+
+	var name string
+	var deleted bool
+	if err := db.QueryRow("SELECT name, deleted_at IS NOT NULL FROM foo WHERE id = ?", a.ID).Scan(&name, &deleted); err != nil || name != "b" || !deleted {
+		t.Fatalf("soft deleted entry: got %s, %t, %v, want b, true", name, deleted, err)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "time"
+
+// Foo is upserted on the conflict field Code
+type Foo struct {
+	ID        int64      `db:"id"`
+	Code      string     `db:"code" cruder:"conflict"`
+	Name      string     `db:"name"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
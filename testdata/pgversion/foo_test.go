@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// Update, Patch and Delete only match the entry with the version that was read
+func TestVersion(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, `CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, version INTEGER NOT NULL);
+		INSERT INTO foo (id, name, version) VALUES (1, 'a', 0), (2, 'b', 0)`)
+
+	a, err := GetFoo(ctx, db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Name = "aa"
+	updated, err := UpdateFoo(ctx, db, *a)
+	if err != nil || updated.Name != "aa" || updated.Version != 1 {
+		t.Fatalf("UpdateFoo: got %v, %v, want version 1", updated, err)
+	}
+	if _, err := UpdateFoo(ctx, db, *a); err != ErrStaleFoo {
+		t.Fatalf("UpdateFoo with an old version: got %v, want ErrStaleFoo", err)
+	}
+	if _, err := UpdateFoo(ctx, db, Foo{ID: 3}); err != ErrStaleFoo {
+		t.Fatalf("UpdateFoo of a missing entry: got %v, want ErrStaleFoo", err)
+	}
+
+	name := "bb"
+	patched, err := PatchFoo(ctx, db, 2, 0, FooPatch{Name: &name})
+	if err != nil || *patched != (Foo{ID: 2, Name: "bb", Version: 1}) {
+		t.Fatalf("PatchFoo: got %v, %v", patched, err)
+	}
+	if _, err := PatchFoo(ctx, db, 2, 0, FooPatch{}); err != ErrStaleFoo {
+		t.Fatalf("PatchFoo with an old version: got %v, want ErrStaleFoo", err)
+	}
+
+	if err := DeleteFoo(ctx, db, 2, 0); err != ErrStaleFoo {
+		t.Fatalf("DeleteFoo with another version: got %v, want ErrStaleFoo", err)
+	}
+	if err := DeleteFoo(ctx, db, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ExistsFoo(ctx, db, 2); err != nil || ok {
+		t.Fatalf("ExistsFoo on a deleted entry: got %t, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+// Foo is locked optimistically with the version
+type Foo struct {
+	ID      int64  `db:"id"`
+	Name    string `db:"name"`
+	Version int64  `db:"version" cruder:"version"`
+}
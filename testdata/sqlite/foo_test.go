@@ -0,0 +1,80 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// Round trip through the functions of the sqlite backend, generated without
+// context.Context
+func TestCRUD(t *testing.T) {
+	db := openDB(t, `CREATE TABLE foo (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		deleted_at DATETIME
+	)`)
+
+	a, err := CreateFoo(db, Foo{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == 0 || a.Name != "a" {
+		t.Fatalf("CreateFoo: got %v", a)
+	}
+	b, err := CreateFoo(db, Foo{Name: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetFoo(db, a.ID)
+	if err != nil || *got != *a {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, a)
+	}
+
+	foos, err := ListFoos(db, 1, 1, nil, sortByName{})
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos with limit and offset: got %v, %v, want [%v]", foos, err, b)
+	}
+	foos, err = ListFoos(db, 0, 0, nameFilter("b"), nil)
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos with filter: got %v, %v, want [%v]", foos, err, b)
+	}
+
+	b.Name = "c"
+	updated, err := UpdateFoo(db, *b)
+	if err != nil || *updated != *b {
+		t.Fatalf("UpdateFoo: got %v, %v, want %v", updated, err, b)
+	}
+
+	if err := DeleteFoo(db, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteFoo(db, a.ID); err == nil {
+		t.Fatal("DeleteFoo on a deleted entry: got no error")
+	}
+	if _, err := GetFoo(db, a.ID); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := UpdateFoo(db, *a); err != sql.ErrNoRows {
+		t.Fatalf("UpdateFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	foos, err = ListFoos(db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos after delete: got %v, %v, want [%v]", foos, err, b)
+	}
+
+	var deleted int
+	if err := db.QueryRow("SELECT COUNT(*) FROM foo WHERE deleted_at IS NOT NULL").Scan(&deleted); err != nil || deleted != 1 {
+		t.Fatalf("soft deleted entries: got %d, %v, want 1", deleted, err)
+	}
+}
+
+type sortByName struct{}
+
+func (sortByName) OrderBy() string { return "name" }
+
+type nameFilter string
+
+func (f nameFilter) Where() (string, []interface{}) {
+	return "name = ?", []interface{}{string(f)}
+}
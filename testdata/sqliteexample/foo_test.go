@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/satori/go.uuid"
+)
+
+// The id is generated by SQLite in the text format of uuid.UUID
+const schema = `CREATE TABLE foo (
+	id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(6)))),
+	name TEXT NOT NULL,
+	deleted_at DATETIME
+)`
+
+// Round trip of the Foo of the example through the functions of the sqlite
+// backend
+func TestCRUD(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, schema)
+
+	a, err := CreateFoo(ctx, db, Foo{Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == uuid.Nil || a.Name != "a" {
+		t.Fatalf("CreateFoo: got %v", a)
+	}
+	b, err := CreateFoo(ctx, db, Foo{Name: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetFoo(ctx, db, a.ID)
+	if err != nil || *got != *a {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, a)
+	}
+
+	foos, err := ListFoos(ctx, db, 1, 1, nil, sortByName{})
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos with limit and offset: got %v, %v, want [%v]", foos, err, b)
+	}
+	foos, err = ListFoos(ctx, db, 0, 0, nameFilter("b"), nil)
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos with filter: got %v, %v, want [%v]", foos, err, b)
+	}
+
+	b.Name = "c"
+	updated, err := UpdateFoo(ctx, db, *b)
+	if err != nil || *updated != *b {
+		t.Fatalf("UpdateFoo: got %v, %v, want %v", updated, err, b)
+	}
+
+	if err := DeleteFoo(ctx, db, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteFoo(ctx, db, a.ID); err == nil {
+		t.Fatal("DeleteFoo on a deleted entry: got no error")
+	}
+	if _, err := GetFoo(ctx, db, a.ID); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	if _, err := UpdateFoo(ctx, db, *a); err != sql.ErrNoRows {
+		t.Fatalf("UpdateFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+	foos, err = ListFoos(ctx, db, 0, 0, nil, nil)
+	if err != nil || len(foos) != 1 || foos[0] != *b {
+		t.Fatalf("ListFoos after delete: got %v, %v, want [%v]", foos, err, b)
+	}
+
+	var deleted int
+	if err := db.QueryRow("SELECT COUNT(*) FROM foo WHERE deleted_at IS NOT NULL").Scan(&deleted); err != nil || deleted != 1 {
+		t.Fatalf("soft deleted entries: got %d, %v, want 1", deleted, err)
+	}
+}
+
+type sortByName struct{}
+
+func (sortByName) OrderBy() string { return "name" }
+
+type nameFilter string
+
+func (f nameFilter) Where() (string, []interface{}) {
+	return "name = ?", []interface{}{string(f)}
+}
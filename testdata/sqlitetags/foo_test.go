@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// The functions are generated without context.Context and use the fields as
+// configured by the struct tags of Foo
+func TestTags(t *testing.T) {
+	db := openDB(t, `CREATE TABLE foo (
+		key INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		serial INTEGER NOT NULL DEFAULT 7,
+		inserted DATETIME NOT NULL,
+		changed DATETIME NOT NULL,
+		removed DATETIME
+	)`)
+
+	a, err := CreateFoo(db, Foo{Name: "a", Secret: "s", Serial: 1, Cached: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Key == 0 || a.Name != "a" || a.Secret != "" || a.Serial != 7 || a.Cached != "" || a.Inserted.IsZero() || a.Changed.IsZero() {
+		t.Fatalf("CreateFoo: got %v", a)
+	}
+
+	var secret string
+	if err := db.QueryRow("SELECT secret FROM foo WHERE key = ?", a.Key).Scan(&secret); err != nil || secret != "s" {
+		t.Fatalf("written secret: got %q, %v, want s", secret, err)
+	}
+
+	// Moves the timestamps back to see which ones the update changes
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := db.Exec("UPDATE foo SET inserted = ?, changed = ?", old, old); err != nil {
+		t.Fatal(err)
+	}
+	a.Name = "b"
+	a.Serial = 1
+	updated, err := UpdateFoo(db, *a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "b" || updated.Serial != 7 || !updated.Inserted.Equal(old) || !updated.Changed.After(old) {
+		t.Fatalf("UpdateFoo: got %v, want Serial and Inserted unchanged and Changed set by the database", updated)
+	}
+
+	got, err := GetFoo(db, a.Key)
+	if err != nil || *got != *updated {
+		t.Fatalf("GetFoo: got %v, %v, want %v", got, err, updated)
+	}
+
+	if err := DeleteFoo(db, a.Key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetFoo(db, a.Key); err != sql.ErrNoRows {
+		t.Fatalf("GetFoo on a deleted entry: got %v, want sql.ErrNoRows", err)
+	}
+}
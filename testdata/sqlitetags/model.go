@@ -0,0 +1,15 @@
+package main
+
+import "time"
+
+// Foo configures its fields with cruder struct tags
+type Foo struct {
+	Key      int64      `db:"key" cruder:"pk"`
+	Name     string     `db:"name"`
+	Secret   string     `db:"secret" cruder:"writeonly"`
+	Serial   int64      `db:"serial" cruder:"readonly"`
+	Cached   string     `db:"cached" cruder:"-"`
+	Inserted time.Time  `db:"inserted" cruder:"autocreate"`
+	Changed  time.Time  `db:"changed" cruder:"autoupdate"`
+	Removed  *time.Time `db:"removed" cruder:"softdelete"`
+}